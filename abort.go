@@ -0,0 +1,227 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jepomeroy/gh-bulk/internal/campaign"
+	"github.com/jepomeroy/gh-bulk/internal/config"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// abortOptions holds the flags of the abort command.
+type abortOptions struct {
+	owners  []string
+	comment string
+	yes     bool
+	output  string
+}
+
+func newAbortCmd() *cobra.Command {
+	opts := &abortOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "abort <branch-or-run>",
+		Short: "Close the pull requests opened from a branch and delete the branch",
+		Long: `Close every open pull request opened from a branch and delete the branch that gh-bulk
+pushed to each repository.
+
+The argument is either the branch name or the id of a recorded run. For a run, the
+repositories of the run are checked for the branch, including those where no pull request
+was opened; otherwise only the repositories with a pull request from the branch are.
+
+Without a terminal to confirm on, --yes is required.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAbort(opts, args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	addOwnerFlag(cmd, &opts.owners)
+	flags.StringVar(&opts.comment, "comment", "", "Comment to leave on each pull request before closing it")
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt")
+	flags.StringVarP(&opts.output, "output", "o", "table", "Format of the report: table, json, csv or markdown")
+
+	return cmd
+}
+
+func runAbort(opts *abortOptions, branchOrRun string) error {
+	format, err := report.ParseFormat(opts.output)
+	if err != nil {
+		return err
+	}
+
+	// Closing pull requests and deleting branches cannot be undone, so it is never done
+	// without either a confirmation or --yes.
+	interactive := isInteractive()
+	if !interactive && !opts.yes {
+		return errors.New("--yes is required when stdin is not a terminal")
+	}
+
+	branch, owners, host := resolveCampaign(branchOrRun, opts.owners)
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	owners, err = resolveOwners(client, host, owners)
+	if err != nil {
+		return err
+	}
+
+	ctx := ownerContext(owners)
+
+	prs, err := campaign.FindPullRequests(client, ctx, branch)
+	if err != nil {
+		return err
+	}
+
+	var repos []repo.Repository
+	if journal, err := config.LoadJournal(branchOrRun); err == nil {
+		repos, err = repo.GetRepositories(client, ctx, repoFullNames(journal))
+		if err != nil {
+			return err
+		}
+	} else {
+		// Without a run, a branch of the same name may not be gh-bulk's, so only the
+		// branches pull requests were opened from are deleted.
+		repos = pullRequestRepos(prs)
+	}
+
+	open := []campaign.PullRequest{}
+	for _, pr := range prs {
+		if pr.State == "open" {
+			open = append(open, pr)
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Checking %d repositories for branch %s...\n", len(repos), branch)
+	branched := []repo.Repository{}
+	for _, r := range repos {
+		exists, err := r.HasRemoteBranch(client, branch)
+		if err != nil {
+			return fmt.Errorf("checking branch %s of %s: %w", branch, r.FullName, err)
+		}
+
+		if exists {
+			branched = append(branched, r)
+		}
+	}
+
+	if len(open) == 0 && len(branched) == 0 {
+		fmt.Printf("No open pull requests or branches found for %s\n", branch)
+		return nil
+	}
+
+	if interactive && !opts.yes {
+		if !confirm("Abort campaign", makeAbortDescription(branch, open, branched)) {
+			fmt.Println("Aborting...")
+			return nil
+		}
+	}
+
+	results := abortCampaign(client, branch, opts.comment, open, branched)
+
+	return printReport(format, results)
+}
+
+// abortCampaign closes prs and deletes branch from each of repos, returning a result for
+// every repository touched.
+func abortCampaign(client *api.RESTClient, branch string, comment string, prs []campaign.PullRequest, repos []repo.Repository) []report.Result {
+	results := []report.Result{}
+	closed := map[string]int{}
+
+	for _, pr := range prs {
+		r := pr.Repo()
+		start := time.Now()
+		result := report.Result{Repository: pr.Repository, PRURL: pr.URL, Status: report.StatusClosed}
+
+		r.Logf("Closing pull request %s", pr.URL)
+		err := r.ClosePR(pr.URL, comment)
+		if err != nil {
+			r.Logf("Error closing PR: %s", err)
+			result.Fail(report.StepClose, err)
+		}
+
+		result.Duration = time.Since(start)
+		closed[pr.Repository] = len(results)
+		results = append(results, result)
+	}
+
+	for _, r := range repos {
+		start := time.Now()
+		result := report.Result{Repository: r.FullName, Status: report.StatusBranchDeleted}
+
+		i, hadPR := closed[r.FullName]
+		if hadPR {
+			result = results[i]
+		}
+
+		r.Logf("Deleting branch %s", branch)
+		err := r.DeleteRemoteBranch(client, branch)
+		if err != nil {
+			r.Logf("Error deleting branch: %s", err)
+			if result.Status != report.StatusFailed {
+				result.Fail(report.StepDelete, err)
+			}
+		}
+
+		result.Duration += time.Since(start)
+		if hadPR {
+			results[i] = result
+		} else {
+			results = append(results, result)
+		}
+	}
+
+	return results
+}
+
+// pullRequestRepos returns the repository of each of prs, once each.
+func pullRequestRepos(prs []campaign.PullRequest) []repo.Repository {
+	repos := []repo.Repository{}
+	seen := map[string]bool{}
+	for _, pr := range prs {
+		if !seen[pr.Repository] {
+			seen[pr.Repository] = true
+			repos = append(repos, pr.Repo())
+		}
+	}
+
+	return repos
+}
+
+// repoFullNames returns the owner/name of every repository in journal.
+func repoFullNames(journal *config.Journal) []string {
+	names := []string{}
+	for _, r := range journal.Repos {
+		names = append(names, r.Repository)
+	}
+
+	return names
+}
+
+func makeAbortDescription(branch string, prs []campaign.PullRequest, repos []repo.Repository) string {
+	var description strings.Builder
+
+	fmt.Fprintf(&description, "%-20s %s\n\n", "branch:", branch)
+	description.WriteString("Pull requests to close:\n")
+	for _, pr := range prs {
+		fmt.Fprintf(&description, "  %s\n", pr.URL)
+	}
+
+	description.WriteString("\nRepositories to delete the branch from:\n")
+	for _, r := range repos {
+		fmt.Fprintf(&description, "  %s\n", r.FullName)
+	}
+
+	return description.String()
+}
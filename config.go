@@ -0,0 +1,309 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/jepomeroy/gh-bulk/internal/config"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+func newConfigCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Show or change the gh-bulk configuration",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigShow()
+		},
+	}
+
+	cmd.AddCommand(newConfigShowCmd(), newConfigSetCmd(), newConfigCommitCmd(), newConfigSetsCmd())
+
+	return cmd
+}
+
+func newConfigShowCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show",
+		Short: "Show the configured entries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigShow()
+		},
+	}
+}
+
+func newConfigSetCmd() *cobra.Command {
+	var userType string
+	var org string
+	var owners []string
+	var transport string
+
+	cmd := &cobra.Command{
+		Use:   "set",
+		Short: "Configure the entry for the current gh login",
+		Long: `Configure whether the current gh login operates as an individual or for an organization.
+Entries are kept per host, so --hostname configures the login on another GitHub host.
+
+Without --type the entry is prompted for, which requires stdin to be a terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigSet(userType, org, owners, transport)
+		},
+	}
+
+	cmd.Flags().StringVar(&userType, "type", "", "Entry type: individual or organization")
+	cmd.Flags().StringVar(&org, "org", "", "Organization name, required for organization entries")
+	cmd.Flags().StringVar(&transport, "transport", "", "Clone and push over ssh or https (default ssh)")
+	cmd.Flags().StringSliceVar(&owners, "owner", nil, "Additional user or organization whose repositories are included (repeatable)")
+
+	return cmd
+}
+
+func newConfigCommitCmd() *cobra.Command {
+	var identity repo.Identity
+
+	cmd := &cobra.Command{
+		Use:   "commit",
+		Short: "Show or change who bulk commits are authored by and how they are signed",
+		Long: `Show or change the commit identity of the entry for the current gh login.
+
+By default commits are authored and committed with the name and email from git config,
+falling back to the gh user. Flags override them and set up commit signing; pass an empty
+value to clear a setting.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runConfigCommit(cmd, identity)
+		},
+	}
+
+	cmd.Flags().StringVar(&identity.Name, "name", "", "Commit author name")
+	cmd.Flags().StringVar(&identity.Email, "email", "", "Commit author email")
+	cmd.Flags().StringVar(&identity.CommitterName, "committer-name", "", "Committer name (default: the author name)")
+	cmd.Flags().StringVar(&identity.CommitterEmail, "committer-email", "", "Committer email (default: the author email)")
+	cmd.Flags().StringVar(&identity.Signing, "sign", "", "Sign commits with gpg or ssh")
+	cmd.Flags().StringVar(&identity.SigningKey, "signing-key", "", "GPG key id or SSH key path to sign with")
+
+	return cmd
+}
+
+func newConfigSetsCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "sets [<name>]",
+		Short: "List the saved repository sets, or the repositories of one set",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 1 {
+				return runConfigSetsShow(args[0])
+			}
+
+			return runConfigSets()
+		},
+	}
+}
+
+func runConfigShow() error {
+	c, err := config.LoadConfig("")
+	if err != nil {
+		return err
+	}
+
+	if len(c.ConfigEntries) == 0 {
+		fmt.Println("No entries configured")
+		return nil
+	}
+
+	t := term.FromEnv()
+	width, _, _ := t.Size()
+	tp := tableprinter.New(os.Stdout, t.IsTerminalOutput(), width)
+	tp.AddHeader([]string{"NAME", "HOST", "TYPE", "AUTH USER", "OWNERS", "TRANSPORT"})
+	for _, entry := range c.ConfigEntries {
+		tp.AddField(entry.Name)
+		tp.AddField(cmp.Or(entry.Host, "github.com"))
+		tp.AddField(entry.Type.String())
+		tp.AddField(entry.AuthUser)
+		tp.AddField(strings.Join(entry.Owners, ", "))
+		tp.AddField(cmp.Or(entry.Transport, string(repo.ProtocolSSH)))
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+func runConfigSet(userType string, org string, owners []string, transport string) error {
+	_, err := repo.ParseProtocol(transport)
+	if err != nil {
+		return err
+	}
+
+	host := resolveHost("")
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	user, c, err := loadLogin(client, host)
+	if err != nil {
+		return err
+	}
+
+	if userType == "" {
+		if !isInteractive() {
+			return fmt.Errorf("--type is required when stdin is not a terminal")
+		}
+
+		_, err = c.AddEntry(user.Login)
+		if err != nil || transport == "" {
+			return err
+		}
+
+		entry, _ := c.GetEntry(user.Login)
+		entry.Transport = transport
+		return c.SetEntry(entry)
+	}
+
+	entryType, err := config.ParseUserType(userType)
+	if err != nil {
+		return err
+	}
+
+	authUser := user.Login
+	if entryType == config.OrganizationType {
+		if org == "" {
+			return fmt.Errorf("--org is required for organization entries")
+		}
+
+		authUser = org
+	}
+
+	// The commit identity is changed with config commit and kept here, as is the transport
+	// unless it is given.
+	existing, _ := c.GetEntry(user.Login)
+	if transport == "" {
+		transport = existing.Transport
+	}
+
+	return c.SetEntry(config.ConfigEntry{
+		Name:      user.Login,
+		Type:      entryType,
+		AuthUser:  authUser,
+		Owners:    owners,
+		Transport: transport,
+		Commit:    existing.Commit,
+	})
+}
+
+func runConfigCommit(cmd *cobra.Command, flags repo.Identity) error {
+	host := resolveHost("")
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	user, c, err := loadLogin(client, host)
+	if err != nil {
+		return err
+	}
+
+	entry, ok := c.GetEntry(user.Login)
+	if !ok {
+		return fmt.Errorf("no gh-bulk config for %s: run gh bulk config set first", user.Login)
+	}
+
+	changed := false
+	for name, field := range map[string]*string{
+		"name":            &entry.Commit.Name,
+		"email":           &entry.Commit.Email,
+		"committer-name":  &entry.Commit.CommitterName,
+		"committer-email": &entry.Commit.CommitterEmail,
+		"sign":            &entry.Commit.Signing,
+		"signing-key":     &entry.Commit.SigningKey,
+	} {
+		if cmd.Flags().Changed(name) {
+			value, _ := cmd.Flags().GetString(name)
+			*field = value
+			changed = true
+		}
+	}
+
+	if changed {
+		err = entry.Commit.ValidateSigning()
+		if err != nil {
+			return err
+		}
+
+		err = c.SetEntry(entry)
+		if err != nil {
+			return err
+		}
+	}
+
+	identity, err := resolveIdentity(entry, user, host)
+	if err != nil {
+		return err
+	}
+
+	committerName, committerEmail := identity.Committer()
+	fmt.Printf("%-10s %s <%s>\n", "author:", identity.Name, identity.Email)
+	fmt.Printf("%-10s %s <%s>\n", "committer:", committerName, committerEmail)
+	if identity.Signing == "" {
+		fmt.Printf("%-10s %s\n", "signing:", "off")
+	} else {
+		fmt.Printf("%-10s %s %s\n", "signing:", identity.Signing, identity.SigningKey)
+	}
+
+	return nil
+}
+
+func runConfigSets() error {
+	sets, err := config.LoadRepoSets(resolveHost(""))
+	if err != nil {
+		return err
+	}
+
+	names := sets.Names()
+	if len(names) == 0 {
+		fmt.Println("No repository sets saved")
+		return nil
+	}
+
+	t := term.FromEnv()
+	width, _, _ := t.Size()
+	tp := tableprinter.New(os.Stdout, t.IsTerminalOutput(), width)
+	tp.AddHeader([]string{"NAME", "REPOSITORIES"})
+	for _, name := range names {
+		repos, _ := sets.Get(name)
+		tp.AddField(name)
+		tp.AddField(strconv.Itoa(len(repos)))
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+func runConfigSetsShow(name string) error {
+	sets, err := config.LoadRepoSets(resolveHost(""))
+	if err != nil {
+		return err
+	}
+
+	repos, err := sets.Get(name)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range repos {
+		fmt.Println(r)
+	}
+
+	return nil
+}
@@ -0,0 +1,304 @@
+// Package campaign finds the pull requests opened by a bulk run so they can be followed up on.
+package campaign
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+)
+
+// PullRequest is a pull request opened from a campaign's head branch. The review,
+// check, and merge fields are only set by LoadStatus.
+type PullRequest struct {
+	Repository     string
+	Number         int
+	Title          string
+	State          string
+	URL            string
+	Draft          bool
+	HeadSHA        string
+	ReviewDecision string
+	Checks         string
+	Mergeable      string
+}
+
+type searchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		RepositoryURL string `json:"repository_url"`
+		Number        int    `json:"number"`
+		Title         string `json:"title"`
+		State         string `json:"state"`
+		HTMLURL       string `json:"html_url"`
+		PullRequest   struct {
+			MergedAt *string `json:"merged_at"`
+		} `json:"pull_request"`
+	} `json:"items"`
+}
+
+// FindPullRequests returns every pull request of the owners' repositories whose head is branch
+// in the same repository. The search's head qualifier also matches longer branch names and
+// branches of forks, so each result is checked against the pull request's head.
+func FindPullRequests(client *api.RESTClient, ctx context.Context, branch string) ([]PullRequest, error) {
+	qualifiers, err := repo.OwnerQualifiers(client, ctx)
+	if err != nil {
+		return []PullRequest{}, err
+	}
+	query := url.QueryEscape(fmt.Sprintf("is:pr head:%s %s", branch, qualifiers))
+
+	prs := []PullRequest{}
+	found := 0
+	page := 1
+
+	for {
+		var result searchResult
+		err := client.Get(fmt.Sprintf("search/issues?q=%s&per_page=100&page=%d", query, page), &result)
+		if err != nil {
+			return []PullRequest{}, err
+		}
+
+		for _, item := range result.Items {
+			found++
+
+			repository := repositoryName(item.RepositoryURL)
+
+			var pull pullResponse
+			err = client.Get(fmt.Sprintf("repos/%s/pulls/%d", repository, item.Number), &pull)
+			if err != nil {
+				return []PullRequest{}, err
+			}
+
+			if !pull.headIs(repository, branch) {
+				continue
+			}
+
+			state := item.State
+			if item.PullRequest.MergedAt != nil {
+				state = "merged"
+			}
+
+			prs = append(prs, PullRequest{
+				Repository: repository,
+				Number:     item.Number,
+				Title:      item.Title,
+				State:      state,
+				URL:        item.HTMLURL,
+				Draft:      pull.Draft,
+				HeadSHA:    pull.Head.SHA,
+			})
+		}
+
+		if len(result.Items) == 0 || found >= result.TotalCount {
+			break
+		}
+
+		page++
+	}
+
+	return prs, nil
+}
+
+// repositoryName extracts owner/name from an API repository URL.
+func repositoryName(repositoryURL string) string {
+	_, name, found := strings.Cut(repositoryURL, "/repos/")
+	if !found {
+		return repositoryURL
+	}
+
+	return name
+}
+
+const (
+	// ReviewApproved means at least one reviewer approved and none requested changes.
+	ReviewApproved = "approved"
+	// ReviewChangesRequested means a reviewer's latest review requested changes.
+	ReviewChangesRequested = "changes requested"
+	// ReviewRequired means no reviewer has approved or requested changes yet.
+	ReviewRequired = "review required"
+
+	// ChecksPassing means every check run and commit status succeeded.
+	ChecksPassing = "passing"
+	// ChecksFailing means at least one check run or commit status failed.
+	ChecksFailing = "failing"
+	// ChecksPending means no check failed but some have not finished.
+	ChecksPending = "pending"
+	// ChecksNone means the head commit has no check runs or commit statuses.
+	ChecksNone = "none"
+)
+
+type pullResponse struct {
+	Head struct {
+		SHA  string `json:"sha"`
+		Ref  string `json:"ref"`
+		Repo *struct {
+			FullName string `json:"full_name"`
+		} `json:"repo"`
+	} `json:"head"`
+	Draft          bool   `json:"draft"`
+	MergeableState string `json:"mergeable_state"`
+}
+
+// headIs reports whether the head of the pull request is branch in repository itself, rather
+// than a branch with a longer name or a branch of a fork. The head repository is missing when
+// the fork was deleted.
+func (p pullResponse) headIs(repository string, branch string) bool {
+	return p.Head.Ref == branch && p.Head.Repo != nil && strings.EqualFold(p.Head.Repo.FullName, repository)
+}
+
+type review struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+type checkRun struct {
+	Status     string `json:"status"`
+	Conclusion string `json:"conclusion"`
+}
+
+type checkRunsResponse struct {
+	CheckRuns []checkRun `json:"check_runs"`
+}
+
+type combinedStatus struct {
+	State      string `json:"state"`
+	TotalCount int    `json:"total_count"`
+}
+
+// LoadStatus fills in the head commit, review decision, check state, and mergeability of pr.
+// Only open pull requests are looked up, since the rest can no longer change.
+func LoadStatus(client *api.RESTClient, pr *PullRequest) error {
+	if pr.State != "open" {
+		return nil
+	}
+
+	prPath := fmt.Sprintf("repos/%s/pulls/%d", pr.Repository, pr.Number)
+
+	var pull pullResponse
+	err := client.Get(prPath, &pull)
+	if err != nil {
+		return err
+	}
+
+	pr.HeadSHA = pull.Head.SHA
+	pr.Draft = pull.Draft
+	pr.Mergeable = pull.MergeableState
+
+	var reviews []review
+	err = client.Get(prPath+"/reviews?per_page=100", &reviews)
+	if err != nil {
+		return err
+	}
+
+	pr.ReviewDecision = reviewDecision(reviews)
+
+	commitPath := fmt.Sprintf("repos/%s/commits/%s", pr.Repository, pr.HeadSHA)
+
+	var runs checkRunsResponse
+	err = client.Get(commitPath+"/check-runs?per_page=100", &runs)
+	if err != nil {
+		return err
+	}
+
+	var status combinedStatus
+	err = client.Get(commitPath+"/status", &status)
+	if err != nil {
+		return err
+	}
+
+	pr.Checks = checkState(runs.CheckRuns, status)
+
+	return nil
+}
+
+// reviewDecision summarizes reviews, which are ordered oldest first, by the latest
+// approving or change-requesting review of each reviewer.
+func reviewDecision(reviews []review) string {
+	latest := map[string]string{}
+	for _, r := range reviews {
+		switch r.State {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[r.User.Login] = r.State
+		}
+	}
+
+	decision := ReviewRequired
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewChangesRequested
+		case "APPROVED":
+			decision = ReviewApproved
+		}
+	}
+
+	return decision
+}
+
+// checkState combines check runs and the legacy commit status into a single state.
+func checkState(runs []checkRun, status combinedStatus) string {
+	if len(runs) == 0 && status.TotalCount == 0 {
+		return ChecksNone
+	}
+
+	state := ChecksPassing
+	for _, run := range runs {
+		if run.Status != "completed" {
+			state = ChecksPending
+			continue
+		}
+
+		switch run.Conclusion {
+		case "failure", "cancelled", "timed_out", "action_required", "startup_failure":
+			return ChecksFailing
+		}
+	}
+
+	if status.TotalCount > 0 {
+		switch status.State {
+		case "failure", "error":
+			return ChecksFailing
+		case "pending":
+			state = ChecksPending
+		}
+	}
+
+	return state
+}
+
+// Repo returns the repository pr belongs to.
+func (pr PullRequest) Repo() repo.Repository {
+	owner, name, _ := strings.Cut(pr.Repository, "/")
+
+	var host string
+	if u, err := url.Parse(pr.URL); err == nil {
+		host = u.Host
+	}
+
+	return repo.Repository{Host: host, Owner: owner, Name: name, FullName: pr.Repository}
+}
+
+// ReadyToMerge reports whether pr is open, not a draft, approved, and has no failing or
+// pending checks.
+// When it is not, the reason explains why.
+func (pr PullRequest) ReadyToMerge() (bool, string) {
+	switch {
+	case pr.State != "open":
+		return false, "pull request is " + pr.State
+	case pr.Draft || pr.Mergeable == "draft":
+		return false, "pull request is a draft"
+	case pr.ReviewDecision != ReviewApproved:
+		return false, pr.ReviewDecision
+	case pr.Checks != ChecksPassing && pr.Checks != ChecksNone:
+		return false, "checks " + pr.Checks
+	case pr.Mergeable == "dirty":
+		return false, "merge conflicts"
+	default:
+		return true, ""
+	}
+}
@@ -0,0 +1,130 @@
+package campaign
+
+import "testing"
+
+func TestRepositoryName(t *testing.T) {
+	for in, want := range map[string]string{
+		"https://api.github.com/repos/octo-org/repo-a":         "octo-org/repo-a",
+		"https://ghe.example.com/api/v3/repos/octo-org/repo-b": "octo-org/repo-b",
+		"not-a-url": "not-a-url",
+	} {
+		if got := repositoryName(in); got != want {
+			t.Errorf("repositoryName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func reviewBy(login string, state string) review {
+	var r review
+	r.User.Login = login
+	r.State = state
+
+	return r
+}
+
+func TestReviewDecision(t *testing.T) {
+	for name, tc := range map[string]struct {
+		reviews []review
+		want    string
+	}{
+		"no reviews":        {nil, ReviewRequired},
+		"comment only":      {[]review{reviewBy("a", "COMMENTED")}, ReviewRequired},
+		"approved":          {[]review{reviewBy("a", "APPROVED"), reviewBy("b", "COMMENTED")}, ReviewApproved},
+		"changes requested": {[]review{reviewBy("a", "APPROVED"), reviewBy("b", "CHANGES_REQUESTED")}, ReviewChangesRequested},
+		"re-approved":       {[]review{reviewBy("a", "CHANGES_REQUESTED"), reviewBy("a", "APPROVED")}, ReviewApproved},
+		"dismissed":         {[]review{reviewBy("a", "APPROVED"), reviewBy("a", "DISMISSED")}, ReviewRequired},
+	} {
+		if got := reviewDecision(tc.reviews); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestCheckState(t *testing.T) {
+	passed := checkRun{Status: "completed", Conclusion: "success"}
+	failed := checkRun{Status: "completed", Conclusion: "failure"}
+	running := checkRun{Status: "in_progress"}
+
+	for name, tc := range map[string]struct {
+		runs   []checkRun
+		status combinedStatus
+		want   string
+	}{
+		"no checks":      {nil, combinedStatus{State: "pending"}, ChecksNone},
+		"passing":        {[]checkRun{passed}, combinedStatus{}, ChecksPassing},
+		"failing run":    {[]checkRun{running, failed}, combinedStatus{}, ChecksFailing},
+		"pending run":    {[]checkRun{passed, running}, combinedStatus{}, ChecksPending},
+		"failing status": {[]checkRun{passed}, combinedStatus{State: "failure", TotalCount: 1}, ChecksFailing},
+		"status only":    {nil, combinedStatus{State: "success", TotalCount: 2}, ChecksPassing},
+	} {
+		if got := checkState(tc.runs, tc.status); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestReadyToMerge(t *testing.T) {
+	ready := PullRequest{State: "open", ReviewDecision: ReviewApproved, Checks: ChecksPassing, Mergeable: "clean"}
+
+	for name, tc := range map[string]struct {
+		mutate func(*PullRequest)
+		want   bool
+	}{
+		"ready":          {func(pr *PullRequest) {}, true},
+		"no checks":      {func(pr *PullRequest) { pr.Checks = ChecksNone }, true},
+		"merged":         {func(pr *PullRequest) { pr.State = "merged" }, false},
+		"not approved":   {func(pr *PullRequest) { pr.ReviewDecision = ReviewRequired }, false},
+		"checks pending": {func(pr *PullRequest) { pr.Checks = ChecksPending }, false},
+		"conflicts":      {func(pr *PullRequest) { pr.Mergeable = "dirty" }, false},
+		"draft":          {func(pr *PullRequest) { pr.Draft = true }, false},
+		"draft state":    {func(pr *PullRequest) { pr.Mergeable = "draft" }, false},
+	} {
+		pr := ready
+		tc.mutate(&pr)
+
+		got, reason := pr.ReadyToMerge()
+		if got != tc.want {
+			t.Errorf("%s: got %v (%s), want %v", name, got, reason, tc.want)
+		}
+		if !got && reason == "" {
+			t.Errorf("%s: expected a reason", name)
+		}
+	}
+}
+
+func TestPullRequestRepo(t *testing.T) {
+	pr := PullRequest{Repository: "octo-org/repo-a", URL: "https://ghe.example.com/octo-org/repo-a/pull/7"}
+
+	r := pr.Repo()
+	if r.Host != "ghe.example.com" || r.Owner != "octo-org" || r.Name != "repo-a" {
+		t.Errorf("got host %q, owner %q, name %q", r.Host, r.Owner, r.Name)
+	}
+}
+
+func TestPullResponseHeadIs(t *testing.T) {
+	pull := func(ref string, repo string) pullResponse {
+		var p pullResponse
+		p.Head.Ref = ref
+		if repo != "" {
+			p.Head.Repo = &struct {
+				FullName string `json:"full_name"`
+			}{FullName: repo}
+		}
+
+		return p
+	}
+
+	for name, tc := range map[string]struct {
+		pull pullResponse
+		want bool
+	}{
+		"same repository": {pull("fix/deps", "octo-org/repo-a"), true},
+		"longer branch":   {pull("fix/deps-2", "octo-org/repo-a"), false},
+		"fork":            {pull("fix/deps", "someone/repo-a"), false},
+		"deleted fork":    {pull("fix/deps", ""), false},
+	} {
+		if got := tc.pull.headIs("octo-org/repo-a", "fix/deps"); got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+}
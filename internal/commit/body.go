@@ -0,0 +1,64 @@
+package commit
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+)
+
+// BodyData is what a pull request body template can refer to, such as {{.Repository}} or
+// {{.DiffStat}}.
+type BodyData struct {
+	// Repository is the owner/name of the repository, and Owner and Name its parts.
+	Repository string
+	Owner      string
+	Name       string
+	// DefaultBranch is the repository's default branch, and BaseBranch the branch the pull
+	// request targets.
+	DefaultBranch string
+	BaseBranch    string
+	Branch        string
+	Title         string
+	Message       string
+	// Command is the shell command that made the changes.
+	Command string
+	// DiffStat is git's summary of the files the pull request changes.
+	DiffStat string
+	// CampaignID is the id of the run, which gh bulk resume, status, merge and abort take.
+	CampaignID string
+}
+
+// BodyTemplate renders the body of each pull request from a Go text/template.
+type BodyTemplate struct {
+	tmpl *template.Template
+}
+
+// ParseBodyTemplate parses text as a pull request body template and checks that it only
+// refers to the fields of BodyData.
+func ParseBodyTemplate(text string) (*BodyTemplate, error) {
+	tmpl, err := template.New("body").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing pull request body template: %w", err)
+	}
+
+	t := &BodyTemplate{tmpl: tmpl}
+
+	_, err = t.Render(BodyData{})
+	if err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// Render returns the pull request body for data.
+func (t *BodyTemplate) Render(data BodyData) (string, error) {
+	var body strings.Builder
+
+	err := t.tmpl.Execute(&body, data)
+	if err != nil {
+		return "", fmt.Errorf("rendering pull request body template: %w", err)
+	}
+
+	return strings.TrimSpace(body.String()), nil
+}
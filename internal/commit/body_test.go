@@ -0,0 +1,50 @@
+package commit
+
+import "testing"
+
+func TestBodyTemplate(t *testing.T) {
+	tmpl, err := ParseBodyTemplate(`Ran {{.Command}} on {{.Repository}} ({{.DefaultBranch}}).
+
+{{.DiffStat}}
+{{if .CampaignID}}Campaign: {{.CampaignID}}{{end}}
+`)
+	if err != nil {
+		t.Fatalf("ParseBodyTemplate: %v", err)
+	}
+
+	got, err := tmpl.Render(BodyData{
+		Repository:    "octo/api",
+		DefaultBranch: "main",
+		Command:       "go mod tidy",
+		DiffStat:      " go.sum | 2 +-",
+		CampaignID:    "20261017-120000",
+	})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	want := "Ran go mod tidy on octo/api (main).\n\n go.sum | 2 +-\nCampaign: 20261017-120000"
+	if got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParseBodyTemplate_invalid(t *testing.T) {
+	for _, text := range []string{"{{.Repo}}", "{{if .Name}}"} {
+		if _, err := ParseBodyTemplate(text); err == nil {
+			t.Errorf("ParseBodyTemplate(%q): expected error", text)
+		}
+	}
+}
+
+func TestBody(t *testing.T) {
+	c := Commit{CommitMessage: "Tidy modules"}
+	if got := c.Body(); got != "Tidy modules" {
+		t.Errorf("got %q, want the commit message", got)
+	}
+
+	c.PullRequestBody = "Rendered"
+	if got := c.Body(); got != "Rendered" {
+		t.Errorf("got %q, want the rendered body", got)
+	}
+}
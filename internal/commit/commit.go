@@ -3,75 +3,166 @@ package commit
 
 import (
 	"errors"
+	"fmt"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/huh"
 )
 
+const (
+	// BranchNameLimit is the maximum length of a branch name.
+	BranchNameLimit = 80
+	// PullRequestTitleLimit is the maximum length of a pull request title.
+	PullRequestTitleLimit = 80
+	// CommitMessageLimit is the maximum length of a commit message.
+	CommitMessageLimit = 400
+)
+
 // Commit holds the metadata needed to create a branch, commit changes, and open a pull request.
 type Commit struct {
 	BranchName       string
 	PullRequestTitle string
 	CommitMessage    string
+	// BaseBranch is the branch the change starts from and the pull request targets. When
+	// empty each repository's default branch is used.
+	BaseBranch string
+	// PullRequestBody is the body of the pull request, rendered for one repository from a
+	// body template. When empty CommitMessage is used.
+	PullRequestBody string
+	// Reviewers are users or teams, as org/team, asked to review the pull request, and Assignees
+	// the users it is assigned to.
+	Reviewers []string
+	Assignees []string
+	Labels    []string
+	Milestone string
+	Draft     bool
+	// CodeOwners also asks the code owners of the changed paths to review.
+	CodeOwners bool
+}
+
+// Body returns the body of the pull request: PullRequestBody when it is set, and otherwise
+// the commit message.
+func (c Commit) Body() string {
+	if c.PullRequestBody != "" {
+		return c.PullRequestBody
+	}
+
+	return c.CommitMessage
 }
 
-// NewCommit prompts the user interactively for branch name, pull request title, and commit message.
-func NewCommit() (Commit, error) {
-	var branchName string
-	var prTitle string
-	var commitMessage string
-
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewInput().
-				Title("Branch name: ").
-				Value(&branchName).
-				CharLimit(80).
-				Validate(func(s string) error {
-					if len(s) == 0 {
-						return errors.New("Branch name required")
-					}
-
-					// only a-z A-Z 0-9 - _ . / are valid branch name characters
-					for _, c := range s {
-						if !((c >= 'a' && c <= 'z') ||
-							(c >= 'A' && c <= 'Z') ||
-							(c >= '0' && c <= '9') ||
-							c == '-' ||
-							c == '_' ||
-							c == '.' ||
-							c == '/') {
-							return errors.New("Branch name can only contain a-z A-Z 0-9 - _ . /")
-						}
-					}
-
-					return nil
-				}),
-			huh.NewInput().
-				Title("Pull Request title: ").
-				Value(&prTitle).
-				CharLimit(80).
-				Validate(func(s string) error {
-					if len(s) == 0 {
-						return errors.New("Pull Request title required")
-					}
-
-					return nil
-				}),
-			huh.NewText().
-				Title("Commit message: ").
-				Value(&commitMessage).
-				CharLimit(400),
-		),
-	).WithTheme(huh.ThemeCatppuccin())
+// NewCommit prompts the user interactively for whichever of branch name, pull request title, and
+// commit message are empty in c, and returns c with the answers filled in.
+func NewCommit(c Commit) (Commit, error) {
+	fields := []huh.Field{}
+
+	if c.BranchName == "" {
+		fields = append(fields, huh.NewInput().
+			Title("Branch name: ").
+			Value(&c.BranchName).
+			CharLimit(BranchNameLimit).
+			Validate(ValidateBranchName))
+	}
+
+	if c.PullRequestTitle == "" {
+		fields = append(fields, huh.NewInput().
+			Title("Pull Request title: ").
+			Value(&c.PullRequestTitle).
+			CharLimit(PullRequestTitleLimit).
+			Validate(ValidatePullRequestTitle))
+	}
+
+	if c.CommitMessage == "" {
+		fields = append(fields, huh.NewText().
+			Title("Commit message: ").
+			Value(&c.CommitMessage).
+			CharLimit(CommitMessageLimit))
+	}
+
+	if len(fields) == 0 {
+		return c, nil
+	}
+
+	form := huh.NewForm(huh.NewGroup(fields...)).WithTheme(huh.ThemeCatppuccin())
 
 	err := form.Run()
 	if err != nil {
 		return Commit{}, err
 	}
 
-	return Commit{
-		BranchName:       branchName,
-		PullRequestTitle: prTitle,
-		CommitMessage:    commitMessage,
-	}, nil
+	return c, nil
+}
+
+// Validate applies the same rules as the NewCommit prompt to c.
+func (c Commit) Validate() error {
+	err := ValidateBranchName(c.BranchName)
+	if err != nil {
+		return err
+	}
+
+	err = ValidatePullRequestTitle(c.PullRequestTitle)
+	if err != nil {
+		return err
+	}
+
+	err = ValidateCommitMessage(c.CommitMessage)
+	if err != nil {
+		return err
+	}
+
+	if c.BaseBranch != "" {
+		err = ValidateBranchName(c.BaseBranch)
+		if err != nil {
+			return fmt.Errorf("base: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateBranchName reports whether s is an acceptable branch name.
+func ValidateBranchName(s string) error {
+	if len(s) == 0 {
+		return errors.New("Branch name required")
+	}
+
+	if utf8.RuneCountInString(s) > BranchNameLimit {
+		return fmt.Errorf("Branch name must be at most %d characters", BranchNameLimit)
+	}
+
+	// only a-z A-Z 0-9 - _ . / are valid branch name characters
+	for _, c := range s {
+		if !((c >= 'a' && c <= 'z') ||
+			(c >= 'A' && c <= 'Z') ||
+			(c >= '0' && c <= '9') ||
+			c == '-' ||
+			c == '_' ||
+			c == '.' ||
+			c == '/') {
+			return errors.New("Branch name can only contain a-z A-Z 0-9 - _ . /")
+		}
+	}
+
+	return nil
+}
+
+// ValidatePullRequestTitle reports whether s is an acceptable pull request title.
+func ValidatePullRequestTitle(s string) error {
+	if len(s) == 0 {
+		return errors.New("Pull Request title required")
+	}
+
+	if utf8.RuneCountInString(s) > PullRequestTitleLimit {
+		return fmt.Errorf("Pull Request title must be at most %d characters", PullRequestTitleLimit)
+	}
+
+	return nil
+}
+
+// ValidateCommitMessage reports whether s is an acceptable commit message.
+func ValidateCommitMessage(s string) error {
+	if utf8.RuneCountInString(s) > CommitMessageLimit {
+		return fmt.Errorf("Commit message must be at most %d characters", CommitMessageLimit)
+	}
+
+	return nil
 }
@@ -0,0 +1,54 @@
+package commit
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateBranchName(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		wantErr bool
+	}{
+		{"fix/deps", false},
+		{"release-1.2_rc", false},
+		{"", true},
+		{"has space", true},
+		{"emoji-✨", true},
+		{strings.Repeat("a", BranchNameLimit+1), true},
+	} {
+		err := ValidateBranchName(tc.name)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("ValidateBranchName(%q) error = %v, wantErr %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := Commit{BranchName: "fix/deps", PullRequestTitle: "Fix dependencies"}
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	c.PullRequestTitle = ""
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for missing title")
+	}
+
+	c.PullRequestTitle = "Fix dependencies"
+	c.CommitMessage = strings.Repeat("m", CommitMessageLimit+1)
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for long commit message")
+	}
+
+	c.CommitMessage = ""
+	c.BaseBranch = "release/2.x"
+	if err := c.Validate(); err != nil {
+		t.Errorf("unexpected error for base branch: %v", err)
+	}
+
+	c.BaseBranch = "release 2"
+	if err := c.Validate(); err == nil {
+		t.Error("expected error for invalid base branch")
+	}
+}
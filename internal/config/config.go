@@ -2,12 +2,15 @@
 package config
 
 import (
+	"cmp"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
 	"gopkg.in/yaml.v3"
 )
 
@@ -21,26 +24,89 @@ const (
 // UserType distinguishes whether the authenticated user operates as an individual or an organization.
 type UserType int
 
+// String returns the name used for t on the command line.
+func (t UserType) String() string {
+	switch t {
+	case IndividualType:
+		return "individual"
+	case OrganizationType:
+		return "organization"
+	default:
+		return fmt.Sprintf("UserType(%d)", int(t))
+	}
+}
+
+// ParseUserType converts a command line name into a UserType.
+func ParseUserType(s string) (UserType, error) {
+	switch s {
+	case "individual":
+		return IndividualType, nil
+	case "organization", "org":
+		return OrganizationType, nil
+	default:
+		return IndividualType, fmt.Errorf("unknown user type %q, expected individual or organization", s)
+	}
+}
+
+// defaultHost is the host of config entries that do not name one.
+const defaultHost = "github.com"
+
 // ConfigEntry represents a single user's configuration in the gh-bulk config file.
+// AuthUser is the primary owner of the repositories, and Owners lists any further
+// users or organizations whose repositories are included.
 type ConfigEntry struct {
-	Name     string   `yaml:"name"`
+	Name string `yaml:"name"`
+	// Host is the GitHub host the entry is for. It is empty for github.com.
+	Host     string   `yaml:"host,omitempty"`
 	Type     UserType `yaml:"type"`
 	AuthUser string   `yaml:"authUser"`
+	Owners   []string `yaml:"owners,omitempty"`
+	// Transport is ssh or https, how repositories are cloned and pushed to. It defaults to ssh.
+	Transport string `yaml:"transport,omitempty"`
+	// Commit overrides who bulk commits are authored by and configures their signing.
+	Commit repo.Identity `yaml:"commit,omitempty"`
 }
 
-// Config holds all configuration entries loaded from the gh-bulk config file.
+// AllOwners returns AuthUser followed by the additional Owners, without duplicates.
+func (e ConfigEntry) AllOwners() []string {
+	owners := []string{}
+	seen := map[string]bool{}
+
+	for _, owner := range append([]string{e.AuthUser}, e.Owners...) {
+		if owner != "" && !seen[owner] {
+			seen[owner] = true
+			owners = append(owners, owner)
+		}
+	}
+
+	return owners
+}
+
+// Config holds all configuration entries loaded from the gh-bulk config file. Entries are
+// looked up and stored for Host, so that one login can have an entry on each host.
 type Config struct {
 	ConfigEntries []ConfigEntry
+	Host          string
 }
 
-// LoadConfig reads the gh-bulk configuration from disk and returns it.
-func LoadConfig() (*Config, error) {
+// LoadConfig reads the gh-bulk configuration from disk and returns it for looking up the
+// entries of host. An empty host is github.com.
+func LoadConfig(host string) (*Config, error) {
 	entries, err := readConfig()
 	if err != nil {
 		return nil, err
 	}
 
-	return &Config{ConfigEntries: entries}, nil
+	return &Config{ConfigEntries: entries, Host: host}, nil
+}
+
+// matches reports whether entry is the entry named entryName on the host of c.
+func (c *Config) matches(entry ConfigEntry, entryName string) bool {
+	return entry.Name == entryName && sameHost(entry.Host, c.Host)
+}
+
+func sameHost(a string, b string) bool {
+	return strings.EqualFold(cmp.Or(a, defaultHost), cmp.Or(b, defaultHost))
 }
 
 func readConfig() ([]ConfigEntry, error) {
@@ -63,27 +129,54 @@ func readConfig() ([]ConfigEntry, error) {
 	return config, nil
 }
 
-// AddEntry prompts for a new config entry for entryName, appends it, and writes the config to disk.
-func (c *Config) AddEntry(entryName string) (string, error) {
+// AddEntry prompts for a config entry for entryName, stores it, writes the config to disk,
+// and returns the entry's owners.
+func (c *Config) AddEntry(entryName string) ([]string, error) {
 	configEntry, err := makeEntry(entryName)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	c.ConfigEntries = append(c.ConfigEntries, configEntry)
+	if existing, ok := c.GetEntry(entryName); ok {
+		configEntry.Transport = existing.Transport
+		configEntry.Commit = existing.Commit
+	}
 
-	err = c.writeConfig()
+	err = c.SetEntry(configEntry)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	return configEntry.AuthUser, nil
+	return configEntry.AllOwners(), nil
+}
+
+// SetEntry replaces the entry with the same name as entry on the host of c, or appends it, and
+// writes the config to disk.
+func (c *Config) SetEntry(entry ConfigEntry) error {
+	entry.Host = ""
+	if !sameHost(c.Host, defaultHost) {
+		entry.Host = c.Host
+	}
+
+	replaced := false
+	for i := range c.ConfigEntries {
+		if c.matches(c.ConfigEntries[i], entry.Name) {
+			c.ConfigEntries[i] = entry
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		c.ConfigEntries = append(c.ConfigEntries, entry)
+	}
+
+	return c.writeConfig()
 }
 
 // HasEntry reports whether a config entry with the given name exists.
 func (c *Config) HasEntry(entryName string) bool {
 	for _, entry := range c.ConfigEntries {
-		if entry.Name == entryName {
+		if c.matches(entry, entryName) {
 			return true
 		}
 	}
@@ -112,6 +205,7 @@ func makeConfigDir() error {
 func makeEntry(entryName string) (ConfigEntry, error) {
 	var entryType UserType
 	var authUser string
+	var additional string
 
 	fmt.Printf("Current GitHub User: %s\n\n", entryName)
 	form := huh.NewForm(
@@ -130,6 +224,12 @@ func makeEntry(entryName string) (ConfigEntry, error) {
 				Placeholder("Org name").
 				Value(&authUser),
 		).WithHideFunc(func() bool { return entryType == IndividualType }),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Additional owners").
+				Description("Comma separated users or organizations whose repositories are also included").
+				Value(&additional),
+		),
 	).WithTheme(huh.ThemeCatppuccin())
 
 	err := form.Run()
@@ -141,17 +241,60 @@ func makeEntry(entryName string) (ConfigEntry, error) {
 		authUser = entryName
 	}
 
+	owners := []string{}
+	for _, owner := range strings.Split(additional, ",") {
+		owner = strings.TrimSpace(owner)
+		if owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+
 	return ConfigEntry{
 		Name:     entryName,
 		Type:     entryType,
 		AuthUser: authUser,
+		Owners:   owners,
 	}, nil
 }
 
+// GetOwners returns every owner of the config entry matching entryName, starting with its authUser.
+func (c *Config) GetOwners(entryName string) ([]string, error) {
+	for _, entry := range c.ConfigEntries {
+		if c.matches(entry, entryName) {
+			return entry.AllOwners(), nil
+		}
+	}
+
+	return nil, fmt.Errorf("entry %s not found", entryName)
+}
+
+// GetEntry returns the config entry matching entryName, and whether there is one.
+func (c *Config) GetEntry(entryName string) (ConfigEntry, bool) {
+	for _, entry := range c.ConfigEntries {
+		if c.matches(entry, entryName) {
+			return entry, true
+		}
+	}
+
+	return ConfigEntry{}, false
+}
+
+// GetIdentity returns the commit identity of the config entry matching entryName, which is
+// empty when there is no such entry.
+func (c *Config) GetIdentity(entryName string) repo.Identity {
+	for _, entry := range c.ConfigEntries {
+		if c.matches(entry, entryName) {
+			return entry.Commit
+		}
+	}
+
+	return repo.Identity{}
+}
+
 // GetAuthUser returns the authUser value for the config entry matching entryName.
 func (c *Config) GetAuthUser(entryName string) (string, error) {
 	for _, entry := range c.ConfigEntries {
-		if entry.Name == entryName {
+		if c.matches(entry, entryName) {
 			return entry.AuthUser, nil
 		}
 	}
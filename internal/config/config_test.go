@@ -1,7 +1,10 @@
 package config
 
 import (
+	"strings"
 	"testing"
+
+	"github.com/jepomeroy/gh-bulk/internal/repo"
 )
 
 func TestHasEntry(t *testing.T) {
@@ -39,7 +42,7 @@ func TestGetAuthUser_notFound(t *testing.T) {
 func TestLoadConfig_empty(t *testing.T) {
 	t.Setenv("GH_CONFIG_DIR", t.TempDir())
 
-	c, err := LoadConfig()
+	c, err := LoadConfig("")
 	if err != nil {
 		t.Fatalf("LoadConfig: %v", err)
 	}
@@ -65,7 +68,7 @@ func TestWriteReadRoundTrip(t *testing.T) {
 		t.Fatalf("writeConfig: %v", err)
 	}
 
-	loaded, err := LoadConfig()
+	loaded, err := LoadConfig("")
 	if err != nil {
 		t.Fatalf("LoadConfig after write: %v", err)
 	}
@@ -83,3 +86,117 @@ func TestWriteReadRoundTrip(t *testing.T) {
 		t.Errorf("got %q, want %q", authUser, "bob-org")
 	}
 }
+
+func TestSetEntry(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	if err := makeConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Config{ConfigEntries: []ConfigEntry{{Name: "alice", AuthUser: "alice"}}}
+	if err := c.SetEntry(ConfigEntry{Name: "alice", Type: OrganizationType, AuthUser: "alice-org"}); err != nil {
+		t.Fatalf("SetEntry: %v", err)
+	}
+	if err := c.SetEntry(ConfigEntry{Name: "bob", AuthUser: "bob"}); err != nil {
+		t.Fatalf("SetEntry: %v", err)
+	}
+
+	loaded, err := LoadConfig("")
+	if err != nil {
+		t.Fatalf("LoadConfig: %v", err)
+	}
+	if len(loaded.ConfigEntries) != 2 {
+		t.Fatalf("expected 2 entries, got %d", len(loaded.ConfigEntries))
+	}
+	authUser, err := loaded.GetAuthUser("alice")
+	if err != nil {
+		t.Fatalf("GetAuthUser: %v", err)
+	}
+	if authUser != "alice-org" {
+		t.Errorf("got %q, want %q", authUser, "alice-org")
+	}
+}
+
+func TestParseUserType(t *testing.T) {
+	for _, want := range []UserType{IndividualType, OrganizationType} {
+		got, err := ParseUserType(want.String())
+		if err != nil {
+			t.Fatalf("ParseUserType(%q): %v", want.String(), err)
+		}
+		if got != want {
+			t.Errorf("ParseUserType(%q) = %v, want %v", want.String(), got, want)
+		}
+	}
+
+	if _, err := ParseUserType("team"); err == nil {
+		t.Error("expected error for unknown user type")
+	}
+}
+
+func TestGetOwners(t *testing.T) {
+	c := &Config{ConfigEntries: []ConfigEntry{
+		{Name: "alice", AuthUser: "alice-org", Owners: []string{"platform-org", "alice-org", "tools-org"}},
+	}}
+
+	got, err := c.GetOwners("alice")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{"alice-org", "platform-org", "tools-org"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if _, err := c.GetOwners("nobody"); err == nil {
+		t.Error("expected error for missing entry")
+	}
+}
+
+func TestGetIdentity(t *testing.T) {
+	c := &Config{ConfigEntries: []ConfigEntry{
+		{Name: "alice", AuthUser: "alice", Commit: repo.Identity{Email: "alice@example.com", Signing: "ssh", SigningKey: "~/.ssh/id_ed25519.pub"}},
+	}}
+
+	if got := c.GetIdentity("alice"); got.Email != "alice@example.com" || got.Signing != "ssh" {
+		t.Errorf("unexpected identity: %+v", got)
+	}
+
+	if got := c.GetIdentity("nobody"); got != (repo.Identity{}) {
+		t.Errorf("expected an empty identity, got %+v", got)
+	}
+}
+
+func TestSetEntry_perHost(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	if err := makeConfigDir(); err != nil {
+		t.Fatal(err)
+	}
+
+	c := &Config{ConfigEntries: []ConfigEntry{{Name: "alice", AuthUser: "alice"}}, Host: "ghe.example.com"}
+	if c.HasEntry("alice") {
+		t.Error("HasEntry(\"alice\") on ghe.example.com: expected false")
+	}
+	if err := c.SetEntry(ConfigEntry{Name: "alice", AuthUser: "platform"}); err != nil {
+		t.Fatalf("SetEntry: %v", err)
+	}
+
+	for host, want := range map[string]string{"": "alice", "GitHub.com": "alice", "ghe.example.com": "platform"} {
+		loaded, err := LoadConfig(host)
+		if err != nil {
+			t.Fatalf("LoadConfig(%q): %v", host, err)
+		}
+		if len(loaded.ConfigEntries) != 2 {
+			t.Fatalf("expected 2 entries, got %d", len(loaded.ConfigEntries))
+		}
+		authUser, err := loaded.GetAuthUser("alice")
+		if err != nil {
+			t.Fatalf("GetAuthUser on %q: %v", host, err)
+		}
+		if authUser != want {
+			t.Errorf("GetAuthUser on %q: got %q, want %q", host, authUser, want)
+		}
+	}
+}
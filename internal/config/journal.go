@@ -0,0 +1,165 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"github.com/jepomeroy/gh-bulk/internal/plan"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+	"gopkg.in/yaml.v3"
+)
+
+// JournalRepo is the state of a single repository within a run. An empty Status
+// means the repository has not been processed yet.
+type JournalRepo struct {
+	Repository string        `yaml:"repository"`
+	Status     report.Status `yaml:"status,omitempty"`
+	Step       report.Step   `yaml:"step,omitempty"`
+	Error      string        `yaml:"error,omitempty"`
+	PRURL      string        `yaml:"prUrl,omitempty"`
+}
+
+// Finished reports whether the repository needs no further processing.
+func (r JournalRepo) Finished() bool {
+	switch r.Status {
+	case report.StatusPROpened, report.StatusPRUpdated, report.StatusUnchanged:
+		return true
+	default:
+		return false
+	}
+}
+
+// Journal records the plan of a run and the state of each of its repositories, so that
+// an interrupted run can be resumed. It is rewritten on disk after every change.
+type Journal struct {
+	ID      string        `yaml:"id"`
+	Created time.Time     `yaml:"created"`
+	Plan    plan.Plan     `yaml:"plan"`
+	Repos   []JournalRepo `yaml:"repos"`
+
+	mu sync.Mutex
+}
+
+// NewJournal creates and writes a journal for a run of p over repos, given as owner/name.
+func NewJournal(p plan.Plan, repos []string) (*Journal, error) {
+	now := time.Now()
+	j := &Journal{
+		ID:      now.Format("20060102-150405") + "-" + strings.ReplaceAll(p.Branch, "/", "-"),
+		Created: now,
+		Plan:    p,
+	}
+
+	for _, name := range repos {
+		j.Repos = append(j.Repos, JournalRepo{Repository: name})
+	}
+
+	err := os.MkdirAll(journalDir(), 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j, j.write()
+}
+
+// LoadJournal reads the journal of the run with the given id.
+func LoadJournal(id string) (*Journal, error) {
+	data, err := os.ReadFile(journalPath(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no run with id %s", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var j Journal
+	err = yaml.Unmarshal(data, &j)
+	if err != nil {
+		return nil, fmt.Errorf("parsing journal %s: %w", id, err)
+	}
+
+	return &j, nil
+}
+
+// ListJournals returns the ids of every recorded run, oldest first.
+func ListJournals() ([]string, error) {
+	entries, err := os.ReadDir(journalDir())
+	if errors.Is(err, os.ErrNotExist) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return []string{}, err
+	}
+
+	ids := []string{}
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".yaml"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+// Record stores result as the state of its repository and writes the journal to disk.
+// It is safe to call from several goroutines.
+func (j *Journal) Record(result report.Result) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	for i := range j.Repos {
+		if j.Repos[i].Repository == result.Repository {
+			j.Repos[i] = JournalRepo{
+				Repository: result.Repository,
+				Status:     result.Status,
+				Step:       result.Step,
+				Error:      result.ErrorText(),
+				PRURL:      result.PRURL,
+			}
+		}
+	}
+
+	return j.write()
+}
+
+// Pending returns the repositories that have not finished, as owner/name.
+func (j *Journal) Pending() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	pending := []string{}
+	for _, r := range j.Repos {
+		if !r.Finished() {
+			pending = append(pending, r.Repository)
+		}
+	}
+
+	return pending
+}
+
+func (j *Journal) write() error {
+	data, err := yaml.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(journalPath(j.ID), data, 0o644)
+}
+
+func journalDir() string {
+	return filepath.Join(config.ConfigDir(), "gh-bulk", "runs")
+}
+
+func journalPath(id string) string {
+	return filepath.Join(journalDir(), id+".yaml")
+}
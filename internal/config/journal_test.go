@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jepomeroy/gh-bulk/internal/plan"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+)
+
+func TestJournal_roundTrip(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	p := plan.Plan{Filter: repo.Filter{Query: "svc"}, Branch: "chore/tidy", Title: "Tidy", Command: "go mod tidy"}
+	j, err := NewJournal(p, []string{"octo/repo-a", "octo/repo-b", "octo/repo-c"})
+	if err != nil {
+		t.Fatalf("NewJournal: %v", err)
+	}
+	if !strings.HasSuffix(j.ID, "-chore-tidy") {
+		t.Errorf("unexpected id %q", j.ID)
+	}
+
+	for _, result := range []report.Result{
+		{Repository: "octo/repo-a", Status: report.StatusPROpened, PRURL: "https://github.com/octo/repo-a/pull/1"},
+		{Repository: "octo/repo-b", Status: report.StatusFailed, Step: report.StepPush, Err: errors.New("rejected")},
+	} {
+		if err := j.Record(result); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+
+	loaded, err := LoadJournal(j.ID)
+	if err != nil {
+		t.Fatalf("LoadJournal: %v", err)
+	}
+	if loaded.Plan.Command != "go mod tidy" {
+		t.Errorf("plan command: got %q", loaded.Plan.Command)
+	}
+	if got := loaded.Repos[1]; got.Status != report.StatusFailed || got.Step != report.StepPush || got.Error != "rejected" {
+		t.Errorf("unexpected repo state: %+v", got)
+	}
+
+	pending := loaded.Pending()
+	if len(pending) != 2 || pending[0] != "octo/repo-b" || pending[1] != "octo/repo-c" {
+		t.Errorf("unexpected pending repos: %v", pending)
+	}
+
+	ids, err := ListJournals()
+	if err != nil {
+		t.Fatalf("ListJournals: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != j.ID {
+		t.Errorf("unexpected journals: %v", ids)
+	}
+}
+
+func TestLoadJournal_missing(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	_, err := LoadJournal("nope")
+	if err == nil {
+		t.Error("expected error for missing journal")
+	}
+}
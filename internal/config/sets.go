@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/config"
+	"gopkg.in/yaml.v3"
+)
+
+// RepoSets holds the saved sets of repositories of one host, each a list of owner/name.
+// Sets are kept per host, since a bare name means a different repository on another host.
+type RepoSets struct {
+	host string
+	sets map[string][]string
+}
+
+// LoadRepoSets reads the saved sets of repositories of host from disk. An empty host is
+// github.com.
+func LoadRepoSets(host string) (RepoSets, error) {
+	s := RepoSets{host: host, sets: map[string][]string{}}
+
+	data, err := os.ReadFile(s.path())
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return RepoSets{}, err
+	}
+
+	err = yaml.Unmarshal(data, &s.sets)
+	if err != nil {
+		return RepoSets{}, fmt.Errorf("parsing repository sets: %w", err)
+	}
+
+	return s, nil
+}
+
+// Names returns the names of the saved sets in alphabetical order.
+func (s RepoSets) Names() []string {
+	names := []string{}
+	for name := range s.sets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// Get returns the repositories of the set called name.
+func (s RepoSets) Get(name string) ([]string, error) {
+	repos, ok := s.sets[name]
+	if !ok {
+		return nil, fmt.Errorf("no repository set named %s", name)
+	}
+
+	return repos, nil
+}
+
+// Save stores repos as the set called name, replacing any set with that name, and writes the sets to disk.
+func (s RepoSets) Save(name string, repos []string) error {
+	if name == "" {
+		return errors.New("repository set name required")
+	}
+
+	s.sets[name] = repos
+
+	data, err := yaml.Marshal(s.sets)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(s.path()), 0o755)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path(), data, 0o644)
+}
+
+// path returns the file the sets of s are kept in. The sets of github.com predate other hosts
+// and are kept where they were.
+func (s RepoSets) path() string {
+	dir := filepath.Join(config.ConfigDir(), "gh-bulk")
+	if sameHost(s.host, defaultHost) {
+		return filepath.Join(dir, "sets.yaml")
+	}
+
+	return filepath.Join(dir, "sets", strings.ToLower(s.host)+".yaml")
+}
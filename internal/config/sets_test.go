@@ -0,0 +1,57 @@
+package config
+
+import "testing"
+
+func TestRepoSets_roundTrip(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	sets, err := LoadRepoSets("")
+	if err != nil {
+		t.Fatalf("LoadRepoSets: %v", err)
+	}
+	if len(sets.Names()) != 0 {
+		t.Errorf("expected no sets, got %v", sets)
+	}
+
+	if err := sets.Save("payments-services", []string{"octo/payments-api", "octo/payments-worker"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	loaded, err := LoadRepoSets("")
+	if err != nil {
+		t.Fatalf("LoadRepoSets after save: %v", err)
+	}
+	repos, err := loaded.Get("payments-services")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(repos) != 2 || repos[1] != "octo/payments-worker" {
+		t.Errorf("unexpected repos: %v", repos)
+	}
+
+	if _, err := loaded.Get("missing"); err == nil {
+		t.Error("expected error for missing set")
+	}
+}
+
+func TestRepoSets_perHost(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+
+	sets, err := LoadRepoSets("ghe.example.com")
+	if err != nil {
+		t.Fatalf("LoadRepoSets: %v", err)
+	}
+	if err := sets.Save("payments-services", []string{"payments-api"}); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	for host, want := range map[string]bool{"": false, "github.com": false, "GHE.example.com": true} {
+		loaded, err := LoadRepoSets(host)
+		if err != nil {
+			t.Fatalf("LoadRepoSets(%q): %v", host, err)
+		}
+		if _, err := loaded.Get("payments-services"); (err == nil) != want {
+			t.Errorf("set on %q: found = %v, want %v", host, err == nil, want)
+		}
+	}
+}
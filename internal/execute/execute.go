@@ -35,12 +35,15 @@ func GetCommand() (Command, error) {
 	return Command{CommandValue: command}, nil
 }
 
-// Execute runs the command in a shell and returns any error.
-func (c Command) Execute() error {
-	out, err := exec.Command("sh", "-c", c.CommandValue).CombinedOutput()
+// Execute runs the command in a shell with dir as its working directory. When the
+// command fails, the returned error includes its combined output.
+func (c Command) Execute(dir string) error {
+	cmd := exec.Command("sh", "-c", c.CommandValue)
+	cmd.Dir = dir
+
+	out, err := cmd.CombinedOutput()
 	if err != nil {
-		fmt.Println(string(out))
-		return err
+		return fmt.Errorf("%w\n%s", err, out)
 	}
 
 	return nil
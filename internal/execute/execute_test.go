@@ -3,20 +3,24 @@ package execute
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
 func TestExecute_success(t *testing.T) {
-	err := Command{CommandValue: "true"}.Execute()
+	err := Command{CommandValue: "true"}.Execute(t.TempDir())
 	if err != nil {
 		t.Errorf("unexpected error: %v", err)
 	}
 }
 
 func TestExecute_failure(t *testing.T) {
-	err := Command{CommandValue: "false"}.Execute()
+	err := Command{CommandValue: "echo boom; false"}.Execute(t.TempDir())
 	if err == nil {
-		t.Error("expected error from failing command")
+		t.Fatal("expected error from failing command")
+	}
+	if !strings.Contains(err.Error(), "boom") {
+		t.Errorf("expected command output in error, got %q", err)
 	}
 }
 
@@ -24,7 +28,7 @@ func TestExecute_sideEffect(t *testing.T) {
 	dir := t.TempDir()
 	marker := filepath.Join(dir, "marker")
 
-	err := Command{CommandValue: "touch " + marker}.Execute()
+	err := Command{CommandValue: "touch " + marker}.Execute(dir)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -32,3 +36,15 @@ func TestExecute_sideEffect(t *testing.T) {
 		t.Errorf("marker file not created: %v", err)
 	}
 }
+
+func TestExecute_workingDirectory(t *testing.T) {
+	dir := t.TempDir()
+
+	err := Command{CommandValue: "touch relative-marker"}.Execute(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "relative-marker")); err != nil {
+		t.Errorf("marker file not created in working directory: %v", err)
+	}
+}
@@ -0,0 +1,225 @@
+// Package plan loads and validates YAML plan files that drive a bulk run without prompts.
+package plan
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/jepomeroy/gh-bulk/internal/commit"
+	"github.com/jepomeroy/gh-bulk/internal/execute"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"gopkg.in/yaml.v3"
+)
+
+// Plan declares everything a bulk run needs: which repositories to process,
+// the command to run in each, and the branch, commit, and pull request to create.
+type Plan struct {
+	// Host is the GitHub host the repositories are on. It is empty for the host gh defaults to.
+	Host  string `yaml:"host,omitempty"`
+	Owner string `yaml:"owner,omitempty"`
+	// Owners lists further users or organizations whose repositories are selected from.
+	Owners []string `yaml:"owners,omitempty"`
+	// Filter selects the repositories to process, unless Repos lists them explicitly.
+	repo.Filter `yaml:",inline"`
+	Repos       []string `yaml:"repos,omitempty"`
+	Branch      string   `yaml:"branch"`
+	Title       string   `yaml:"title"`
+	Message     string   `yaml:"message"`
+	Command     string   `yaml:"command"`
+	// Body is a Go text/template for the body of each pull request, and BodyFile the path of
+	// a file holding one. Without either the commit message is the body.
+	Body     string `yaml:"body,omitempty"`
+	BodyFile string `yaml:"bodyFile,omitempty"`
+	// Reviewers, Assignees, Labels, Milestone and Draft are set on every pull request.
+	// Reviewers are users or teams, as org/team.
+	Reviewers []string `yaml:"reviewers,omitempty"`
+	Assignees []string `yaml:"assignees,omitempty"`
+	Labels    []string `yaml:"labels,omitempty"`
+	Milestone string   `yaml:"milestone,omitempty"`
+	Draft     bool     `yaml:"draft,omitempty"`
+	// RepoReviewers replaces Reviewers for the repositories it lists by owner/name or name, and
+	// ReviewersFile is the path of a YAML file holding it.
+	RepoReviewers RepoReviewers `yaml:"repoReviewers,omitempty"`
+	ReviewersFile string        `yaml:"reviewersFile,omitempty"`
+	// CodeOwners also requests review from the code owners of the paths each pull request
+	// changes.
+	CodeOwners bool `yaml:"codeOwners,omitempty"`
+	// Base is the branch to start from and open pull requests against, instead of each
+	// repository's default branch.
+	Base string `yaml:"base,omitempty"`
+	// Update reuses the branch and pull request left by an earlier run instead of
+	// failing when they already exist.
+	Update bool `yaml:"update,omitempty"`
+	// Clone is the clone strategy: full, shallow, blobless or mirror. SparsePaths limits
+	// the checkout of every repository to those directories.
+	Clone       string   `yaml:"clone,omitempty"`
+	SparsePaths []string `yaml:"sparsePaths,omitempty"`
+	// ForcePush recreates the branch from the base branch and force-pushes it
+	// when updating, rather than adding a commit on top of the existing branch.
+	ForcePush bool `yaml:"forcePush,omitempty"`
+}
+
+// Read parses the plan file at path without validating it, so that it can be
+// completed from flags or prompts first.
+func Read(path string) (Plan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Plan{}, err
+	}
+
+	var p Plan
+	err = yaml.Unmarshal(data, &p)
+	if err != nil {
+		return Plan{}, fmt.Errorf("parsing plan %s: %w", path, err)
+	}
+
+	return p, nil
+}
+
+// Validate reports whether p describes a complete run.
+func (p Plan) Validate() error {
+	if p.Filter.IsZero() && len(p.Repos) == 0 {
+		return errors.New("either a query, filters or repos is required")
+	}
+
+	if !p.Filter.IsZero() && len(p.Repos) > 0 {
+		return errors.New("query and filters are mutually exclusive with repos")
+	}
+
+	err := p.Filter.Validate()
+	if err != nil {
+		return err
+	}
+
+	err = p.Commit().Validate()
+	if err != nil {
+		return err
+	}
+
+	if p.Command == "" {
+		return errors.New("Command required")
+	}
+
+	if p.ForcePush && !p.Update {
+		return errors.New("forcePush requires update")
+	}
+
+	_, err = repo.ParseCloneStrategy(p.Clone)
+	if err != nil {
+		return err
+	}
+
+	if p.Body != "" && p.BodyFile != "" {
+		return errors.New("body and bodyFile are mutually exclusive")
+	}
+
+	if p.Body != "" {
+		_, err = commit.ParseBodyTemplate(p.Body)
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(p.RepoReviewers) > 0 && p.ReviewersFile != "" {
+		return errors.New("repoReviewers and reviewersFile are mutually exclusive")
+	}
+
+	return nil
+}
+
+// LoadBody reads the body template from BodyFile into Body, so that the template is kept
+// with the plan of the run even if the file changes.
+func (p *Plan) LoadBody() error {
+	if p.BodyFile == "" {
+		return nil
+	}
+
+	if p.Body != "" {
+		return errors.New("body and bodyFile are mutually exclusive")
+	}
+
+	data, err := os.ReadFile(p.BodyFile)
+	if err != nil {
+		return fmt.Errorf("reading pull request body template: %w", err)
+	}
+
+	p.Body = string(data)
+	p.BodyFile = ""
+
+	return nil
+}
+
+// AllOwners returns Owner followed by Owners, leaving out empty values.
+func (p Plan) AllOwners() []string {
+	owners := []string{}
+	for _, owner := range append([]string{p.Owner}, p.Owners...) {
+		if owner != "" {
+			owners = append(owners, owner)
+		}
+	}
+
+	return owners
+}
+
+// Commit returns the branch, commit, and pull request metadata declared by p.
+func (p Plan) Commit() commit.Commit {
+	return commit.Commit{
+		BranchName:       p.Branch,
+		PullRequestTitle: p.Title,
+		CommitMessage:    p.Message,
+		BaseBranch:       p.Base,
+		Reviewers:        p.Reviewers,
+		Assignees:        p.Assignees,
+		Labels:           p.Labels,
+		Milestone:        p.Milestone,
+		Draft:            p.Draft,
+		CodeOwners:       p.CodeOwners,
+	}
+}
+
+// LoadReviewers reads the reviewers of each repository from ReviewersFile into RepoReviewers,
+// so that they are kept with the plan of the run.
+func (p *Plan) LoadReviewers() error {
+	if p.ReviewersFile == "" {
+		return nil
+	}
+
+	if len(p.RepoReviewers) > 0 {
+		return errors.New("repoReviewers and reviewersFile are mutually exclusive")
+	}
+
+	reviewers, err := ReadRepoReviewers(p.ReviewersFile)
+	if err != nil {
+		return err
+	}
+
+	p.RepoReviewers = reviewers
+	p.ReviewersFile = ""
+
+	return nil
+}
+
+// BodyTemplate returns the pull request body template of p, or nil when it has none. It
+// assumes p is valid and its body loaded.
+func (p Plan) BodyTemplate() *commit.BodyTemplate {
+	if p.Body == "" {
+		return nil
+	}
+
+	t, _ := commit.ParseBodyTemplate(p.Body)
+
+	return t
+}
+
+// CloneOptions returns how p clones each repository. It assumes p is valid.
+func (p Plan) CloneOptions() repo.CloneOptions {
+	strategy, _ := repo.ParseCloneStrategy(p.Clone)
+
+	return repo.CloneOptions{Strategy: strategy, SparsePaths: p.SparsePaths}
+}
+
+// ExecCommand returns the command declared by p.
+func (p Plan) ExecCommand() execute.Command {
+	return execute.Command{CommandValue: p.Command}
+}
@@ -0,0 +1,180 @@
+package plan
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+)
+
+func writePlan(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "plan.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestRead(t *testing.T) {
+	path := writePlan(t, `
+repos:
+  - repo-a
+  - other-org/repo-b
+branch: fix/deps
+title: Fix dependencies
+message: Update go.mod and go.sum
+command: go mod tidy
+`)
+
+	p, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if len(p.Repos) != 2 || p.Repos[1] != "other-org/repo-b" {
+		t.Errorf("unexpected repos: %v", p.Repos)
+	}
+	if got := p.Commit().BranchName; got != "fix/deps" {
+		t.Errorf("branch: got %q, want %q", got, "fix/deps")
+	}
+	if got := p.ExecCommand().CommandValue; got != "go mod tidy" {
+		t.Errorf("command: got %q, want %q", got, "go mod tidy")
+	}
+}
+
+func TestRead_missingFile(t *testing.T) {
+	_, err := Read(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil {
+		t.Error("expected error for missing plan file")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	valid := Plan{Filter: repo.Filter{Query: "svc"}, Branch: "fix/deps", Title: "Fix", Command: "true"}
+
+	for name, tc := range map[string]struct {
+		mutate  func(*Plan)
+		wantErr bool
+	}{
+		"valid":           {func(p *Plan) {}, false},
+		"no selection":    {func(p *Plan) { p.Query = "" }, true},
+		"query and repos": {func(p *Plan) { p.Repos = []string{"repo-a"} }, true},
+		"bad branch":      {func(p *Plan) { p.Branch = "fix deps" }, true},
+		"missing title":   {func(p *Plan) { p.Title = "" }, true},
+		"missing command": {func(p *Plan) { p.Command = "" }, true},
+		"repos not query": {func(p *Plan) { p.Query = ""; p.Repos = []string{"repo-a"} }, false},
+		"force no update": {func(p *Plan) { p.ForcePush = true }, true},
+		"force update":    {func(p *Plan) { p.Update = true; p.ForcePush = true }, false},
+		"filters only":    {func(p *Plan) { p.Query = ""; p.Language = "go" }, false},
+		"filters + repos": {func(p *Plan) { p.Query = ""; p.Language = "go"; p.Repos = []string{"repo-a"} }, true},
+		"bad visibility":  {func(p *Plan) { p.Visibility = "secret" }, true},
+		"clone strategy":  {func(p *Plan) { p.Clone = "blobless"; p.SparsePaths = []string{"api"} }, false},
+		"bad clone":       {func(p *Plan) { p.Clone = "treeless" }, true},
+		"bad base":        {func(p *Plan) { p.Base = "release 2" }, true},
+		"body template":   {func(p *Plan) { p.Body = "Ran {{.Command}} on {{.Repository}}" }, false},
+		"bad body":        {func(p *Plan) { p.Body = "{{.Repo}}" }, true},
+		"body and file":   {func(p *Plan) { p.Body = "body"; p.BodyFile = "body.md" }, true},
+	} {
+		p := valid
+		tc.mutate(&p)
+
+		err := p.Validate()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestRead_filters(t *testing.T) {
+	path := writePlan(t, `
+query: svc-
+language: go
+topics: [payments]
+hasFiles:
+  - go.mod
+branch: fix/deps
+title: Fix dependencies
+command: go mod tidy
+`)
+
+	p, err := Read(path)
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	if p.Query != "svc-" || p.Language != "go" || len(p.Topics) != 1 || len(p.HasFiles) != 1 {
+		t.Errorf("unexpected filter: %+v", p.Filter)
+	}
+}
+
+func TestAllOwners(t *testing.T) {
+	p := Plan{Owner: "octo", Owners: []string{"hub", ""}}
+
+	got := p.AllOwners()
+	if len(got) != 2 || got[0] != "octo" || got[1] != "hub" {
+		t.Errorf("got %v, want [octo hub]", got)
+	}
+
+	if got := (Plan{}).AllOwners(); len(got) != 0 {
+		t.Errorf("empty plan: got %v", got)
+	}
+}
+
+func TestLoadBody(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "body.md")
+	if err := os.WriteFile(path, []byte("Campaign {{.CampaignID}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Plan{BodyFile: path}
+	if err := p.LoadBody(); err != nil {
+		t.Fatalf("LoadBody: %v", err)
+	}
+	if p.Body != "Campaign {{.CampaignID}}\n" || p.BodyFile != "" {
+		t.Errorf("got body %q and file %q", p.Body, p.BodyFile)
+	}
+	if p.BodyTemplate() == nil {
+		t.Error("BodyTemplate: expected a template")
+	}
+}
+
+func TestLoadReviewers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "reviewers.yaml")
+	content := "octo/payments-api: [alice, octo/payments]\nweb: [bob]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	p := Plan{ReviewersFile: path}
+	if err := p.LoadReviewers(); err != nil {
+		t.Fatalf("LoadReviewers: %v", err)
+	}
+	if p.ReviewersFile != "" {
+		t.Errorf("expected the file to be cleared, got %q", p.ReviewersFile)
+	}
+
+	for _, tc := range []struct {
+		owner, name string
+		want        []string
+		listed      bool
+	}{
+		{"octo", "payments-api", []string{"alice", "octo/payments"}, true},
+		{"Octo", "Payments-API", []string{"alice", "octo/payments"}, true},
+		{"octo", "web", []string{"bob"}, true},
+		{"octo", "search", nil, false},
+	} {
+		got, listed := p.RepoReviewers.For(tc.owner, tc.name)
+		if listed != tc.listed || !slices.Equal(got, tc.want) {
+			t.Errorf("For(%q, %q) = %v, %v, want %v, %v", tc.owner, tc.name, got, listed, tc.want, tc.listed)
+		}
+	}
+}
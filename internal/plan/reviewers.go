@@ -0,0 +1,46 @@
+package plan
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoReviewers maps repositories, by owner/name or name, to the users and teams, as org/team,
+// asked to review their pull requests.
+type RepoReviewers map[string][]string
+
+// ReadRepoReviewers reads a YAML file mapping each repository to its reviewers:
+//
+//	octo-org/payments-api: [alice, octo-org/payments]
+//	web: [bob]
+func ReadRepoReviewers(path string) (RepoReviewers, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var reviewers RepoReviewers
+	err = yaml.Unmarshal(data, &reviewers)
+	if err != nil {
+		return nil, fmt.Errorf("parsing reviewers %s: %w", path, err)
+	}
+
+	return reviewers, nil
+}
+
+// For returns the reviewers listed for the repository owner/name, preferring an owner/name
+// entry to a name entry, and whether it is listed.
+func (rr RepoReviewers) For(owner string, name string) ([]string, bool) {
+	for _, key := range []string{owner + "/" + name, name} {
+		for listed, reviewers := range rr {
+			if strings.EqualFold(listed, key) {
+				return reviewers, true
+			}
+		}
+	}
+
+	return nil, false
+}
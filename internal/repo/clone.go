@@ -0,0 +1,157 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	ghconfig "github.com/cli/go-gh/v2/pkg/config"
+)
+
+// CloneStrategy is how much of a repository Clone fetches.
+type CloneStrategy string
+
+const (
+	// CloneFull fetches the whole history, as gh repo clone does.
+	CloneFull CloneStrategy = "full"
+	// CloneShallow fetches only the tip commit of each branch.
+	CloneShallow CloneStrategy = "shallow"
+	// CloneBlobless fetches every commit and tree but only the file contents that are checked out.
+	CloneBlobless CloneStrategy = "blobless"
+	// CloneMirror keeps a mirror of the repository under the gh-bulk config directory, fetches
+	// only what changed since the last run into it, and clones from it locally.
+	CloneMirror CloneStrategy = "mirror"
+)
+
+// ParseCloneStrategy converts a command line name into a CloneStrategy. An empty name is full.
+func ParseCloneStrategy(s string) (CloneStrategy, error) {
+	switch CloneStrategy(s) {
+	case "":
+		return CloneFull, nil
+	case CloneFull, CloneShallow, CloneBlobless, CloneMirror:
+		return CloneStrategy(s), nil
+	default:
+		return "", fmt.Errorf("unknown clone strategy %q, expected full, shallow, blobless or mirror", s)
+	}
+}
+
+// CloneOptions controls how Clone fetches and checks out a repository.
+type CloneOptions struct {
+	Strategy CloneStrategy
+	// Transport is used by the clone and by every later fetch and push.
+	Transport Transport
+	// SparsePaths limits the checkout to these directories, plus the files at the top level.
+	SparsePaths []string
+}
+
+// args returns the git clone arguments for the strategy and sparse paths of o.
+func (o CloneOptions) args() []string {
+	args := []string{}
+	switch o.Strategy {
+	case CloneShallow:
+		args = append(args, "--depth", "1", "--no-single-branch")
+	case CloneBlobless:
+		args = append(args, "--filter=blob:none")
+	}
+	if len(o.SparsePaths) > 0 {
+		args = append(args, "--sparse")
+	}
+
+	return args
+}
+
+// usesGitCLI reports whether a clone made with o has to be changed with the git CLI, because
+// go-git cannot fetch missing objects or respect a sparse checkout.
+func (o CloneOptions) usesGitCLI() bool {
+	return o.Strategy == CloneShallow || o.Strategy == CloneBlobless || len(o.SparsePaths) > 0
+}
+
+// cloneSource returns where r is cloned from with o: its mirror, brought up to date, for the
+// mirror strategy and otherwise its URL for the transport of o.
+func (r Repository) cloneSource(o CloneOptions) (string, error) {
+	url := o.Transport.url(r)
+	if o.Strategy != CloneMirror {
+		return url, nil
+	}
+
+	// Mirrors of github.com repositories predate other hosts and are kept where they were.
+	mirrors := filepath.Join(ghconfig.ConfigDir(), "gh-bulk", "mirrors")
+	if r.Host != "" && !strings.EqualFold(r.Host, "github.com") {
+		mirrors = filepath.Join(mirrors, strings.ToLower(r.Host))
+	}
+	mirror := filepath.Join(mirrors, r.Owner, r.Name+".git")
+
+	_, err := os.Stat(mirror)
+	if errors.Is(err, os.ErrNotExist) {
+		r.Logf("Creating mirror in %s", mirror)
+
+		err = os.MkdirAll(filepath.Dir(mirror), 0o755)
+		if err != nil {
+			return "", err
+		}
+
+		return mirror, r.git("", "clone", "--mirror", url, mirror)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	r.Logf("Updating mirror in %s", mirror)
+
+	// The transport may have changed since the mirror was made.
+	err = r.git(mirror, "remote", "set-url", "origin", url)
+	if err != nil {
+		return "", err
+	}
+
+	return mirror, r.git(mirror, "remote", "update", "--prune")
+}
+
+// sparseCheckout limits the checkout in dir to paths. git keeps the sparse checkout settings
+// in a per-worktree config that go-git cannot open, so they are moved to the repository config.
+func (r Repository) sparseCheckout(dir string, paths []string) error {
+	err := r.git(dir, append([]string{"sparse-checkout", "set", "--cone", "--"}, paths...)...)
+	if err != nil {
+		return err
+	}
+
+	for _, args := range [][]string{
+		{"config", "core.sparseCheckout", "true"},
+		{"config", "core.sparseCheckoutCone", "true"},
+		{"config", "--unset", "extensions.worktreeConfig"},
+	} {
+		err = r.git(dir, args...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// git runs git with args in dir, or in the current directory when dir is empty, with the
+// credentials of the transport r was cloned with.
+func (r Repository) git(dir string, args ...string) error {
+	_, err := r.gitOutput(dir, args...)
+	return err
+}
+
+// gitOutput runs git like git does and returns its standard output.
+func (r Repository) gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), r.cloned.Transport.env(r.Host)...)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return string(out), nil
+}
@@ -0,0 +1,46 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseCloneStrategy(t *testing.T) {
+	for in, want := range map[string]CloneStrategy{
+		"":         CloneFull,
+		"full":     CloneFull,
+		"shallow":  CloneShallow,
+		"blobless": CloneBlobless,
+		"mirror":   CloneMirror,
+	} {
+		got, err := ParseCloneStrategy(in)
+		if err != nil || got != want {
+			t.Errorf("ParseCloneStrategy(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseCloneStrategy("treeless"); err == nil {
+		t.Error("expected an error for an unknown strategy")
+	}
+}
+
+func TestCloneOptionsArgs(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts    CloneOptions
+		args    string
+		usesCLI bool
+	}{
+		"full":     {CloneOptions{Strategy: CloneFull}, "", false},
+		"shallow":  {CloneOptions{Strategy: CloneShallow}, "--depth 1 --no-single-branch", true},
+		"blobless": {CloneOptions{Strategy: CloneBlobless}, "--filter=blob:none", true},
+		"mirror":   {CloneOptions{Strategy: CloneMirror}, "", false},
+		"sparse":   {CloneOptions{Strategy: CloneMirror, SparsePaths: []string{"services/api"}}, "--sparse", true},
+	} {
+		if got := strings.Join(tc.opts.args(), " "); got != tc.args {
+			t.Errorf("%s: args %q, want %q", name, got, tc.args)
+		}
+		if got := tc.opts.usesGitCLI(); got != tc.usesCLI {
+			t.Errorf("%s: usesGitCLI %v, want %v", name, got, tc.usesCLI)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package repo
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/jepomeroy/gh-bulk/internal/commit"
+)
+
+// codeownersPaths are where GitHub looks for a CODEOWNERS file, in the order it looks.
+var codeownersPaths = []string{".github/CODEOWNERS", "CODEOWNERS", "docs/CODEOWNERS"}
+
+// codeownersRule is one line of a CODEOWNERS file: the paths it matches and their owners.
+type codeownersRule struct {
+	pattern *regexp.Regexp
+	owners  []string
+}
+
+// parseCodeowners returns the rules of a CODEOWNERS file, leaving out comments and lines
+// whose pattern cannot be used.
+func parseCodeowners(data string) []codeownersRule {
+	rules := []codeownersRule{}
+	for _, line := range strings.Split(data, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		pattern, err := codeownersPattern(fields[0])
+		if err != nil {
+			continue
+		}
+
+		rules = append(rules, codeownersRule{pattern: pattern, owners: fields[1:]})
+	}
+
+	return rules
+}
+
+// codeownersPattern converts a CODEOWNERS path pattern, which follows gitignore rules, into a
+// regular expression matching the paths it covers.
+func codeownersPattern(pattern string) (*regexp.Regexp, error) {
+	// A pattern with a slash other than at its end is relative to the repository root.
+	trimmed := strings.TrimSuffix(pattern, "/")
+	prefix := "^(?:.*/)?"
+	if strings.Contains(trimmed, "/") {
+		prefix = "^"
+	}
+	trimmed = strings.TrimPrefix(trimmed, "/")
+
+	var expr strings.Builder
+	for i := 0; i < len(trimmed); i++ {
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			expr.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			expr.WriteString(".*")
+			i++
+		case trimmed[i] == '*':
+			expr.WriteString("[^/]*")
+		case trimmed[i] == '?':
+			expr.WriteString("[^/]")
+		default:
+			expr.WriteString(regexp.QuoteMeta(trimmed[i : i+1]))
+		}
+	}
+
+	// A pattern naming a directory covers everything in it, but docs/* does not cover the
+	// contents of the directories in docs.
+	last := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	suffix := "$"
+	if !strings.ContainsAny(last, "*?") {
+		suffix = "(?:/.*)?$"
+	}
+
+	return regexp.Compile(prefix + expr.String() + suffix)
+}
+
+// codeowners returns the users and teams, as org/team, that own paths under rules, where the last
+// rule matching a path decides its owners. Owners given by email are left out, as review
+// cannot be requested from them by name.
+func codeowners(rules []codeownersRule, paths []string) []string {
+	owners := []string{}
+	seen := map[string]bool{}
+	for _, path := range paths {
+		var matched []string
+		for _, rule := range rules {
+			if rule.pattern.MatchString(path) {
+				matched = rule.owners
+			}
+		}
+
+		for _, owner := range matched {
+			name, ok := strings.CutPrefix(owner, "@")
+			if !ok || seen[strings.ToLower(name)] {
+				continue
+			}
+
+			seen[strings.ToLower(name)] = true
+			owners = append(owners, name)
+		}
+	}
+
+	return owners
+}
+
+// CodeOwners returns the code owners of the files changed in the worktree since the base
+// branch of commit, from the CODEOWNERS file on the base branch, which is the one GitHub uses.
+func (r Repository) CodeOwners(commit commit.Commit) ([]string, error) {
+	base := r.baseRef(commit)
+
+	// The git CLI reads the base branch whatever the checkout, and fetches what a blobless
+	// clone is missing.
+	var data string
+	for _, path := range codeownersPaths {
+		out, err := r.gitOutput(r.tmpDir, "show", base+":"+path)
+		if err == nil {
+			data = out
+			break
+		}
+	}
+	if data == "" {
+		return []string{}, nil
+	}
+
+	out, err := r.gitOutput(r.tmpDir, "diff", "--no-ext-diff", "--name-only", base)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := strings.Split(strings.TrimSpace(out), "\n")
+
+	return codeowners(parseCodeowners(data), paths), nil
+}
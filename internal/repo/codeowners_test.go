@@ -0,0 +1,60 @@
+package repo
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCodeownersPattern(t *testing.T) {
+	for _, tc := range []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"*", "cmd/main.go", true},
+		{"*.js", "web/src/app.js", true},
+		{"*.js", "web/src/app.ts", false},
+		{"/docs/", "docs/guide/index.md", true},
+		{"/docs/", "api/docs/index.md", false},
+		{"apps/", "services/apps/main.go", true},
+		{"docs/*", "docs/getting-started.md", true},
+		{"docs/*", "docs/build-app/troubleshooting.md", false},
+		{"**/logs", "deep/in/logs/today.log", true},
+		{"/build/logs/", "build/logs/today.log", true},
+		{"scripts/**", "scripts/ci/run.sh", true},
+		{"go.mod", "tools/go.mod", true},
+		{"/go.mod", "tools/go.mod", false},
+	} {
+		pattern, err := codeownersPattern(tc.pattern)
+		if err != nil {
+			t.Fatalf("codeownersPattern(%q): %v", tc.pattern, err)
+		}
+		if got := pattern.MatchString(tc.path); got != tc.want {
+			t.Errorf("%q matching %q = %v, want %v", tc.pattern, tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestCodeowners(t *testing.T) {
+	rules := parseCodeowners(`
+# Everything is owned by the platform team by default
+*           @octo/platform
+*.go        @alice @octo/go-reviewers
+/docs/      docs@example.com @bob
+/docs/api/  # no owners
+`)
+
+	for _, tc := range []struct {
+		paths []string
+		want  []string
+	}{
+		{[]string{"README.md"}, []string{"octo/platform"}},
+		{[]string{"cmd/main.go", "go.mod"}, []string{"alice", "octo/go-reviewers", "octo/platform"}},
+		{[]string{"docs/index.md"}, []string{"bob"}},
+		{[]string{"docs/api/index.md"}, []string{}},
+	} {
+		if got := codeowners(rules, tc.paths); !slices.Equal(got, tc.want) {
+			t.Errorf("codeowners(%v) = %v, want %v", tc.paths, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// codeSearchLimit is the number of results GitHub code search returns at most for a query.
+const codeSearchLimit = 1000
+
+type codeSearchResult struct {
+	TotalCount int `json:"total_count"`
+	Items      []struct {
+		Path       string         `json:"path"`
+		Repository map[string]any `json:"repository"`
+	} `json:"items"`
+}
+
+// CodeSearchRepositories returns the non-archived repositories of the owners with code matching
+// codeQuery, in order of their first match, each with the paths of its matching files in
+// MatchedPaths.
+func CodeSearchRepositories(client *api.RESTClient, ctx context.Context, codeQuery string) ([]Repository, error) {
+	fmt.Fprintln(os.Stderr, "Searching code...")
+	qualifiers, err := OwnerQualifiers(client, ctx)
+	if err != nil {
+		return []Repository{}, err
+	}
+	query := url.QueryEscape(strings.TrimSpace(fmt.Sprintf("%s %s", codeQuery, qualifiers)))
+
+	repos := []Repository{}
+	index := map[string]int{}
+	// unknown holds the repositories whose results do not say whether they are archived.
+	unknown := map[string]bool{}
+	seen := 0
+	page := 1
+
+	for {
+		var result codeSearchResult
+		err := client.Get(fmt.Sprintf("search/code?q=%s&per_page=100&page=%d", query, page), &result)
+		if err != nil {
+			return []Repository{}, err
+		}
+
+		for _, item := range result.Items {
+			r := newRepository(item.Repository)
+
+			i, ok := index[r.FullName]
+			if !ok {
+				i = len(repos)
+				index[r.FullName] = i
+				repos = append(repos, r)
+
+				if _, known := item.Repository["archived"]; !known {
+					unknown[r.FullName] = true
+				}
+			}
+
+			repos[i].MatchedPaths = append(repos[i].MatchedPaths, item.Path)
+		}
+
+		seen += len(result.Items)
+		if len(result.Items) == 0 || seen >= result.TotalCount || seen >= codeSearchLimit {
+			break
+		}
+
+		page++
+	}
+
+	return withoutArchived(client, repos, unknown)
+}
+
+// withoutArchived returns repos without the archived ones. Code search results usually carry
+// a minimal repository without its archived flag, so the repositories named in unknown are
+// looked up, which also fills in the metadata that code search leaves out.
+func withoutArchived(client *api.RESTClient, repos []Repository, unknown map[string]bool) ([]Repository, error) {
+	kept := []Repository{}
+	for _, r := range repos {
+		if unknown[r.FullName] {
+			var result map[string]any
+			err := client.Get("repos/"+r.FullName, &result)
+			if err != nil {
+				return []Repository{}, fmt.Errorf("fetching repository %s: %w", r.FullName, err)
+			}
+
+			matched := r.MatchedPaths
+			r = newRepository(result)
+			r.MatchedPaths = matched
+		}
+
+		if !r.Archived {
+			kept = append(kept, r)
+		}
+	}
+
+	return kept, nil
+}
@@ -0,0 +1,62 @@
+package repo
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// stubTransport answers API requests with the JSON body for their path, and 404 otherwise.
+type stubTransport map[string]string
+
+func (s stubTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := http.StatusOK
+	body, ok := s[strings.TrimPrefix(req.URL.Path, "/")]
+	if !ok {
+		status, body = http.StatusNotFound, `{"message": "Not Found"}`
+	}
+
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+		Request:    req,
+	}, nil
+}
+
+func TestCodeSearchRepositories_archived(t *testing.T) {
+	client, err := api.NewRESTClient(api.ClientOptions{
+		Host:      "github.com",
+		AuthToken: "token",
+		Transport: stubTransport{
+			"users/octo": `{"type": "User"}`,
+			"search/code": `{"total_count": 3, "items": [
+				{"path": "go.mod", "repository": {"name": "api", "full_name": "octo/api", "html_url": "https://github.com/octo/api"}},
+				{"path": "go.mod", "repository": {"name": "old", "full_name": "octo/old", "html_url": "https://github.com/octo/old"}},
+				{"path": "go.mod", "repository": {"name": "gone", "full_name": "octo/gone", "html_url": "https://github.com/octo/gone", "archived": true}}
+			]}`,
+			"repos/octo/api": `{"name": "api", "full_name": "octo/api", "default_branch": "main", "archived": false}`,
+			"repos/octo/old": `{"name": "old", "full_name": "octo/old", "default_branch": "main", "archived": true}`,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.WithValue(context.Background(), AuthUserKey("auth"), []string{"octo"})
+	repos, err := CodeSearchRepositories(client, ctx, "filename:go.mod")
+	if err != nil {
+		t.Fatalf("CodeSearchRepositories: %v", err)
+	}
+
+	if len(repos) != 1 || repos[0].FullName != "octo/api" {
+		t.Fatalf("got %v, want only octo/api", repos)
+	}
+	if repos[0].DefaultBranch != "main" || len(repos[0].MatchedPaths) != 1 {
+		t.Errorf("got default branch %q and matched paths %v", repos[0].DefaultBranch, repos[0].MatchedPaths)
+	}
+}
@@ -0,0 +1,340 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// Filter selects repositories by search qualifiers and by checks that search cannot express,
+// such as a regular expression on the name or the presence of a file.
+type Filter struct {
+	Query string `yaml:"query,omitempty"`
+	// CodeQuery selects the repositories containing code that matches it, instead of searching repositories.
+	CodeQuery   string   `yaml:"codeQuery,omitempty"`
+	Language    string   `yaml:"language,omitempty"`
+	Topics      []string `yaml:"topics,omitempty"`
+	Visibility  string   `yaml:"visibility,omitempty"`
+	Fork        string   `yaml:"fork,omitempty"`
+	PushedSince string   `yaml:"pushedSince,omitempty"`
+	// CanPush limits the repositories to those the user can push to.
+	CanPush bool `yaml:"canPush,omitempty"`
+	// Properties selects by repository custom property, each given as name=value.
+	Properties []string `yaml:"properties,omitempty"`
+	// Team limits the repositories to those the team has at least TeamPermission on.
+	Team           string   `yaml:"team,omitempty"`
+	TeamPermission string   `yaml:"teamPermission,omitempty"`
+	NameRegex      string   `yaml:"nameRegex,omitempty"`
+	HasFiles       []string `yaml:"hasFiles,omitempty"`
+}
+
+// IsZero reports whether f selects nothing beyond the owner's repositories.
+func (f Filter) IsZero() bool {
+	return f.Query == "" &&
+		f.CodeQuery == "" &&
+		f.Language == "" &&
+		len(f.Topics) == 0 &&
+		f.Visibility == "" &&
+		f.Fork == "" &&
+		f.PushedSince == "" &&
+		!f.CanPush &&
+		len(f.Properties) == 0 &&
+		f.Team == "" &&
+		f.TeamPermission == "" &&
+		f.NameRegex == "" &&
+		len(f.HasFiles) == 0
+}
+
+// With returns f with every field that is set in o replaced by o's value.
+func (f Filter) With(o Filter) Filter {
+	if o.Query != "" {
+		f.Query = o.Query
+	}
+	if o.CodeQuery != "" {
+		f.CodeQuery = o.CodeQuery
+	}
+	if o.Language != "" {
+		f.Language = o.Language
+	}
+	if len(o.Topics) > 0 {
+		f.Topics = o.Topics
+	}
+	if o.Visibility != "" {
+		f.Visibility = o.Visibility
+	}
+	if o.Fork != "" {
+		f.Fork = o.Fork
+	}
+	if o.PushedSince != "" {
+		f.PushedSince = o.PushedSince
+	}
+	if o.CanPush {
+		f.CanPush = true
+	}
+	if len(o.Properties) > 0 {
+		f.Properties = o.Properties
+	}
+	if o.Team != "" {
+		f.Team = o.Team
+	}
+	if o.TeamPermission != "" {
+		f.TeamPermission = o.TeamPermission
+	}
+	if o.NameRegex != "" {
+		f.NameRegex = o.NameRegex
+	}
+	if len(o.HasFiles) > 0 {
+		f.HasFiles = o.HasFiles
+	}
+
+	return f
+}
+
+// Validate reports whether every field of f has an accepted value.
+func (f Filter) Validate() error {
+	switch f.Visibility {
+	case "", "public", "private", "internal":
+	default:
+		return fmt.Errorf("unknown visibility %q, expected public, private or internal", f.Visibility)
+	}
+
+	switch f.Fork {
+	case "", "exclude", "include", "only":
+	default:
+		return fmt.Errorf("unknown fork filter %q, expected exclude, include or only", f.Fork)
+	}
+
+	if f.CodeQuery != "" && (f.Query != "" || len(f.Topics) > 0 || f.Visibility != "" || f.Fork != "" || f.PushedSince != "" || f.CanPush || len(f.Properties) > 0) {
+		return errors.New("a code query can only be combined with the language, team, name regex and has file filters")
+	}
+
+	for _, property := range f.Properties {
+		name, value, ok := strings.Cut(property, "=")
+		if !ok || name == "" || value == "" {
+			return fmt.Errorf("custom property %q must be given as name=value", property)
+		}
+	}
+
+	switch f.TeamPermission {
+	case "", "pull", "triage", "push", "maintain", "admin":
+	default:
+		return fmt.Errorf("unknown team permission %q, expected pull, triage, push, maintain or admin", f.TeamPermission)
+	}
+
+	if f.TeamPermission != "" && f.Team == "" {
+		return errors.New("a team permission requires a team")
+	}
+
+	if f.PushedSince != "" {
+		_, err := time.Parse(time.DateOnly, f.PushedSince)
+		if err != nil {
+			return fmt.Errorf("pushed since must be a date like 2024-01-31: %w", err)
+		}
+	}
+
+	if f.NameRegex != "" {
+		_, err := regexp.Compile(f.NameRegex)
+		if err != nil {
+			return fmt.Errorf("invalid name regex: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// SearchQuery returns the repository search query for the qualifiers of f.
+func (f Filter) SearchQuery() string {
+	terms := []string{}
+	if f.Query != "" {
+		terms = append(terms, f.Query)
+	}
+	if f.Language != "" {
+		terms = append(terms, "language:"+f.Language)
+	}
+	for _, topic := range f.Topics {
+		terms = append(terms, "topic:"+topic)
+	}
+	if f.Visibility != "" {
+		terms = append(terms, "is:"+f.Visibility)
+	}
+	switch f.Fork {
+	case "include":
+		terms = append(terms, "fork:true")
+	case "only":
+		terms = append(terms, "fork:only")
+	}
+	if f.PushedSince != "" {
+		terms = append(terms, "pushed:>="+f.PushedSince)
+	}
+	for _, property := range f.Properties {
+		name, value, _ := strings.Cut(property, "=")
+		terms = append(terms, fmt.Sprintf("props.%s:%s", name, value))
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// CodeSearchQuery returns the code search query for the code query and language of f.
+func (f Filter) CodeSearchQuery() string {
+	if f.Language == "" {
+		return f.CodeQuery
+	}
+
+	return f.CodeQuery + " language:" + f.Language
+}
+
+// FindRepositories returns the non-archived repositories of the owners that match f, or the
+// repositories with code matching f.CodeQuery when it is set.
+func FindRepositories(client *api.RESTClient, ctx context.Context, f Filter) ([]Repository, error) {
+	err := f.Validate()
+	if err != nil {
+		return []Repository{}, err
+	}
+
+	var repos []Repository
+	switch {
+	case f.CodeQuery != "":
+		repos, err = CodeSearchRepositories(client, ctx, f.CodeSearchQuery())
+	case f.Team == "" || f.SearchQuery() != "" || f.CanPush:
+		repos, err = ownerRepositories(client, ctx, f)
+	}
+	if err != nil {
+		return []Repository{}, err
+	}
+
+	if f.Team != "" {
+		teamRepos, err := TeamRepositories(client, ctx, f.Team, f.TeamPermission)
+		if err != nil {
+			return []Repository{}, err
+		}
+
+		if repos == nil {
+			repos = teamRepos
+		} else {
+			repos = intersect(repos, teamRepos)
+		}
+	}
+
+	if f.NameRegex != "" {
+		nameRegex := regexp.MustCompile(f.NameRegex)
+		matched := []Repository{}
+		for _, r := range repos {
+			if nameRegex.MatchString(r.Name) {
+				matched = append(matched, r)
+			}
+		}
+		repos = matched
+	}
+
+	if len(f.HasFiles) == 0 {
+		return repos, nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Checking %d repositories for %s...\n", len(repos), strings.Join(f.HasFiles, ", "))
+	matched := []Repository{}
+	for _, r := range repos {
+		ok, err := r.hasFiles(client, f.HasFiles)
+		if err != nil {
+			return []Repository{}, err
+		}
+
+		if ok {
+			matched = append(matched, r)
+		}
+	}
+
+	return matched, nil
+}
+
+// TeamRepositories returns the non-archived repositories that team has at least permission on.
+// The team is given as org/slug, or as a slug of the first owner's organization. An empty
+// permission accepts any.
+func TeamRepositories(client *api.RESTClient, ctx context.Context, team string, permission string) ([]Repository, error) {
+	fmt.Fprintf(os.Stderr, "Fetching repositories of team %s...\n", team)
+	org, slug, found := strings.Cut(team, "/")
+	if !found {
+		org, slug = primaryOwner(ctx), team
+	}
+	if permission == "" {
+		permission = "pull"
+	}
+
+	repos := []Repository{}
+	page := 1
+
+	for {
+		var result []map[string]any
+		err := client.Get(fmt.Sprintf("orgs/%s/teams/%s/repos?per_page=100&page=%d", org, slug, page), &result)
+		if err != nil {
+			return []Repository{}, fmt.Errorf("fetching repositories of team %s: %w", team, err)
+		}
+
+		for _, item := range result {
+			archived, _ := item["archived"].(bool)
+			permissions, _ := item["permissions"].(map[string]any)
+			allowed, _ := permissions[permission].(bool)
+			if !archived && allowed {
+				repos = append(repos, newRepository(item))
+			}
+		}
+
+		if len(result) < 100 {
+			break
+		}
+
+		page++
+	}
+
+	return repos, nil
+}
+
+// intersect returns the repositories of a that are also in b, in the order of a.
+func intersect(a []Repository, b []Repository) []Repository {
+	inB := map[string]bool{}
+	for _, r := range b {
+		inB[r.FullName] = true
+	}
+
+	repos := []Repository{}
+	for _, r := range a {
+		if inB[r.FullName] {
+			repos = append(repos, r)
+		}
+	}
+
+	return repos
+}
+
+func (r Repository) hasFiles(client *api.RESTClient, paths []string) (bool, error) {
+	for _, path := range paths {
+		ok, err := r.HasFile(client, path)
+		if err != nil || !ok {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// HasFile reports whether path exists on the default branch of r.
+func (r Repository) HasFile(client *api.RESTClient, path string) (bool, error) {
+	var content any
+	err := client.Get(fmt.Sprintf("repos/%s/contents/%s", r.FullName, strings.TrimPrefix(path, "/")), &content)
+
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("checking %s in %s: %w", path, r.FullName, err)
+	}
+
+	return true, nil
+}
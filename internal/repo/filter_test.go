@@ -0,0 +1,105 @@
+package repo
+
+import "testing"
+
+func TestFilterSearchQuery(t *testing.T) {
+	f := Filter{
+		Query:       "svc",
+		Language:    "go",
+		Topics:      []string{"payments", "backend"},
+		Visibility:  "private",
+		Fork:        "only",
+		PushedSince: "2024-01-31",
+		NameRegex:   "^svc-",
+		HasFiles:    []string{"go.mod"},
+	}
+
+	want := "svc language:go topic:payments topic:backend is:private fork:only pushed:>=2024-01-31"
+	if got := f.SearchQuery(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if got := (Filter{}).SearchQuery(); got != "" {
+		t.Errorf("empty filter: got %q", got)
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		f       Filter
+		wantErr bool
+	}{
+		"empty":          {Filter{}, false},
+		"valid":          {Filter{Visibility: "internal", Fork: "include", PushedSince: "2024-01-31", NameRegex: "^svc-"}, false},
+		"bad visibility": {Filter{Visibility: "secret"}, true},
+		"bad fork":       {Filter{Fork: "yes"}, true},
+		"bad date":       {Filter{PushedSince: "last week"}, true},
+		"bad regex":      {Filter{NameRegex: "("}, true},
+		"code query":     {Filter{CodeQuery: "old-module", Language: "go", NameRegex: "^svc-"}, false},
+		"code and query": {Filter{CodeQuery: "old-module", Query: "svc"}, true},
+		"team":           {Filter{Team: "payments", TeamPermission: "admin", Properties: []string{"tier=critical"}}, false},
+		"bad permission": {Filter{Team: "payments", TeamPermission: "owner"}, true},
+		"no team":        {Filter{TeamPermission: "admin"}, true},
+		"bad property":   {Filter{Properties: []string{"tier"}}, true},
+	} {
+		err := tc.f.Validate()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestFilterWith(t *testing.T) {
+	f := Filter{Query: "svc", Language: "go"}.With(Filter{Language: "rust", HasFiles: []string{"Cargo.toml"}})
+
+	if f.Query != "svc" || f.Language != "rust" || len(f.HasFiles) != 1 {
+		t.Errorf("unexpected filter: %+v", f)
+	}
+	if f.IsZero() || !(Filter{}).IsZero() {
+		t.Error("IsZero mismatch")
+	}
+}
+
+func TestFilterCodeSearchQuery(t *testing.T) {
+	if got := (Filter{CodeQuery: `"old/module"`}).CodeSearchQuery(); got != `"old/module"` {
+		t.Errorf("got %q", got)
+	}
+	if got := (Filter{CodeQuery: "old", Language: "go"}).CodeSearchQuery(); got != "old language:go" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestRepositoryLabel(t *testing.T) {
+	for name, tc := range map[string]struct {
+		paths []string
+		want  string
+	}{
+		"no matches": {nil, "repo-a"},
+		"matches":    {[]string{"go.mod", "main.go"}, "repo-a (go.mod, main.go)"},
+		"many":       {[]string{"a", "b", "c", "d", "e"}, "repo-a (a, b, c, +2 more)"},
+	} {
+		r := Repository{Name: "repo-a", MatchedPaths: tc.paths}
+		if got := r.Label(); got != tc.want {
+			t.Errorf("%s: got %q, want %q", name, got, tc.want)
+		}
+	}
+}
+
+func TestFilterSearchQuery_properties(t *testing.T) {
+	f := Filter{Topics: []string{"payments"}, Properties: []string{"tier=critical", "team=core"}}
+
+	want := "topic:payments props.tier:critical props.team:core"
+	if got := f.SearchQuery(); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	a := []Repository{{FullName: "o/a"}, {FullName: "o/b"}, {FullName: "o/c"}}
+	b := []Repository{{FullName: "o/c"}, {FullName: "o/a"}}
+
+	got := intersect(a, b)
+	if len(got) != 2 || got[0].FullName != "o/a" || got[1].FullName != "o/c" {
+		t.Errorf("unexpected intersection: %v", got)
+	}
+}
@@ -0,0 +1,114 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Identity is who bulk commits are authored and committed by, and how they are signed.
+type Identity struct {
+	Name  string `yaml:"name,omitempty"`
+	Email string `yaml:"email,omitempty"`
+	// CommitterName and CommitterEmail default to Name and Email.
+	CommitterName  string `yaml:"committerName,omitempty"`
+	CommitterEmail string `yaml:"committerEmail,omitempty"`
+	// Signing is gpg or ssh to sign commits with SigningKey, a GPG key id or the path of an
+	// SSH key. An empty Signing leaves commits unsigned.
+	Signing    string `yaml:"signing,omitempty"`
+	SigningKey string `yaml:"signingKey,omitempty"`
+}
+
+// WithDefaults returns i with every empty name and email taken from fallback.
+func (i Identity) WithDefaults(fallback Identity) Identity {
+	if i.Name == "" {
+		i.Name = fallback.Name
+	}
+	if i.Email == "" {
+		i.Email = fallback.Email
+	}
+	if i.CommitterName == "" {
+		i.CommitterName = fallback.CommitterName
+	}
+	if i.CommitterEmail == "" {
+		i.CommitterEmail = fallback.CommitterEmail
+	}
+
+	return i
+}
+
+// Committer returns the name and email commits are committed with.
+func (i Identity) Committer() (string, string) {
+	name, email := i.CommitterName, i.CommitterEmail
+	if name == "" {
+		name = i.Name
+	}
+	if email == "" {
+		email = i.Email
+	}
+
+	return name, email
+}
+
+// Validate reports whether i names an author and has an accepted signing setup.
+func (i Identity) Validate() error {
+	if i.Name == "" || i.Email == "" {
+		return errors.New("a commit author name and email are required")
+	}
+
+	return i.ValidateSigning()
+}
+
+// ValidateSigning reports whether the signing format and key of i are accepted.
+func (i Identity) ValidateSigning() error {
+	switch i.Signing {
+	case "", "gpg":
+	case "ssh":
+		if i.SigningKey == "" {
+			return errors.New("ssh signing requires a signing key")
+		}
+	default:
+		return fmt.Errorf("unknown signing format %q, expected gpg or ssh", i.Signing)
+	}
+
+	return nil
+}
+
+// GitConfigIdentity returns the author name and email set in the user's git config.
+func GitConfigIdentity() Identity {
+	return Identity{Name: gitConfig("user.name"), Email: gitConfig("user.email")}
+}
+
+func gitConfig(key string) string {
+	out, err := exec.Command("git", "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(out))
+}
+
+// commitArgs returns the git arguments that commit with message as i, signing the commit. An
+// empty message is allowed, as it is for unsigned commits.
+func (i Identity) commitArgs(message string) []string {
+	committerName, committerEmail := i.Committer()
+	args := []string{
+		"-c", "user.name=" + committerName,
+		"-c", "user.email=" + committerEmail,
+	}
+
+	switch i.Signing {
+	case "ssh":
+		args = append(args, "-c", "gpg.format=ssh")
+	case "gpg":
+		args = append(args, "-c", "gpg.format=openpgp")
+	}
+	if i.SigningKey != "" {
+		args = append(args, "-c", "user.signingkey="+i.SigningKey)
+	}
+
+	return append(args, "commit", "--gpg-sign", "--no-verify", "--allow-empty-message",
+		"--author", fmt.Sprintf("%s <%s>", i.Name, i.Email),
+		"--message", message)
+}
@@ -0,0 +1,58 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIdentityWithDefaults(t *testing.T) {
+	configured := Identity{Email: "bot@example.com", Signing: "ssh", SigningKey: "key.pub"}
+
+	got := configured.WithDefaults(Identity{Name: "Octo Cat", Email: "octo@example.com"})
+	if got.Name != "Octo Cat" || got.Email != "bot@example.com" || got.Signing != "ssh" {
+		t.Errorf("unexpected identity: %+v", got)
+	}
+
+	name, email := got.Committer()
+	if name != "Octo Cat" || email != "bot@example.com" {
+		t.Errorf("committer defaults: got %s <%s>", name, email)
+	}
+
+	got.CommitterName = "Release Bot"
+	if name, _ := got.Committer(); name != "Release Bot" {
+		t.Errorf("committer override: got %s", name)
+	}
+}
+
+func TestIdentityValidate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		identity Identity
+		wantErr  bool
+	}{
+		"unsigned":       {Identity{Name: "a", Email: "a@example.com"}, false},
+		"gpg":            {Identity{Name: "a", Email: "a@example.com", Signing: "gpg"}, false},
+		"ssh":            {Identity{Name: "a", Email: "a@example.com", Signing: "ssh", SigningKey: "key.pub"}, false},
+		"ssh no key":     {Identity{Name: "a", Email: "a@example.com", Signing: "ssh"}, true},
+		"unknown format": {Identity{Name: "a", Email: "a@example.com", Signing: "x509"}, true},
+		"no email":       {Identity{Name: "a"}, true},
+	} {
+		err := tc.identity.Validate()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: error = %v, wantErr %v", name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestIdentityCommitArgs(t *testing.T) {
+	i := Identity{Name: "Octo Cat", Email: "octo@example.com", Signing: "ssh", SigningKey: "key.pub"}
+
+	got := strings.Join(i.commitArgs("Tidy"), " ")
+	for _, want := range []string{
+		"-c user.name=Octo Cat", "-c user.email=octo@example.com", "-c gpg.format=ssh",
+		"-c user.signingkey=key.pub", "commit --gpg-sign", "--allow-empty-message", "--author Octo Cat <octo@example.com>", "--message Tidy",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("args missing %q: %s", want, got)
+		}
+	}
+}
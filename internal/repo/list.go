@@ -0,0 +1,41 @@
+package repo
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ReadList reads a file of repositories, one owner/name or bare name per line. Blank lines
+// and everything after a # are ignored.
+func ReadList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	names := []string{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		name, _, _ := strings.Cut(scanner.Text(), "#")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		if strings.ContainsAny(name, " \t") || strings.Count(name, "/") > 1 {
+			return nil, fmt.Errorf("%s:%d: expected owner/name, got %q", path, line, name)
+		}
+
+		names = append(names, name)
+	}
+
+	err = scanner.Err()
+	if err != nil {
+		return nil, err
+	}
+
+	return names, nil
+}
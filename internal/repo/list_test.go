@@ -0,0 +1,50 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeList(t *testing.T, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "repos.txt")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestReadList(t *testing.T) {
+	path := writeList(t, `# payments services
+octo/payments-api
+  octo/payments-worker   # moved in Q3
+
+payments-ledger
+`)
+
+	got, err := ReadList(path)
+	if err != nil {
+		t.Fatalf("ReadList: %v", err)
+	}
+
+	want := []string{"octo/payments-api", "octo/payments-worker", "payments-ledger"}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d: got %q, want %q", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadList_invalid(t *testing.T) {
+	path := writeList(t, "octo/repo-a\nocto/repo b\n")
+
+	if _, err := ReadList(path); err == nil {
+		t.Error("expected error for malformed line")
+	}
+}
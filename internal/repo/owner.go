@@ -0,0 +1,196 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+)
+
+// ownerKinds caches whether each owner looked up by isOrganization is an organization.
+var ownerKinds sync.Map
+
+// isOrganization reports whether owner is an organization rather than a user.
+func isOrganization(client *api.RESTClient, owner string) (bool, error) {
+	if kind, ok := ownerKinds.Load(owner); ok {
+		return kind.(bool), nil
+	}
+
+	var result struct {
+		Type string `json:"type"`
+	}
+	err := client.Get("users/"+owner, &result)
+	if err != nil {
+		return false, fmt.Errorf("looking up owner %s: %w", owner, err)
+	}
+
+	org := result.Type == "Organization"
+	ownerKinds.Store(owner, org)
+
+	return org, nil
+}
+
+// OwnerQualifiers returns the search qualifiers that match any of the owners in ctx, using
+// org: for organizations and user: for users.
+func OwnerQualifiers(client *api.RESTClient, ctx context.Context) (string, error) {
+	qualifiers := []string{}
+	for _, owner := range Owners(ctx) {
+		org, err := isOrganization(client, owner)
+		if err != nil {
+			return "", err
+		}
+
+		qualifiers = append(qualifiers, ownerQualifier(owner, org))
+	}
+
+	return strings.Join(qualifiers, " "), nil
+}
+
+func ownerQualifier(owner string, org bool) string {
+	if org {
+		return "org:" + owner
+	}
+
+	return "user:" + owner
+}
+
+// ownerRepositories returns the non-archived repositories of the owners in ctx matching the
+// search qualifiers of f. Organizations are listed through the organization repositories API
+// whenever f can be checked without search, which sees every repository the user can access
+// and is not capped at 1000 results; other owners are searched.
+func ownerRepositories(client *api.RESTClient, ctx context.Context, f Filter) ([]Repository, error) {
+	repos := []Repository{}
+	searched := []string{}
+
+	for _, owner := range Owners(ctx) {
+		org, err := isOrganization(client, owner)
+		if err != nil {
+			return []Repository{}, err
+		}
+
+		if !org || !f.listable() {
+			searched = append(searched, ownerQualifier(owner, org))
+			continue
+		}
+
+		listed, err := OrgRepositories(client, owner, f)
+		if err != nil {
+			return []Repository{}, err
+		}
+		repos = append(repos, listed...)
+	}
+
+	if len(searched) > 0 {
+		found, err := searchRepositories(client, f.SearchQuery(), strings.Join(searched, " "))
+		if err != nil {
+			return []Repository{}, err
+		}
+		repos = append(repos, found...)
+	}
+
+	if f.CanPush {
+		pushable := []Repository{}
+		for _, r := range repos {
+			if r.canPush {
+				pushable = append(pushable, r)
+			}
+		}
+		repos = pushable
+	}
+
+	return repos, nil
+}
+
+// OrgRepositories returns the non-archived repositories of org that the user can see and
+// that match the search qualifiers of f, which must be listable.
+func OrgRepositories(client *api.RESTClient, org string, f Filter) ([]Repository, error) {
+	fmt.Fprintf(os.Stderr, "Fetching repositories of %s...\n", org)
+	repos := []Repository{}
+	page := 1
+
+	for {
+		var result []map[string]any
+		err := client.Get(fmt.Sprintf("orgs/%s/repos?type=all&sort=full_name&per_page=100&page=%d", org, page), &result)
+		if err != nil {
+			return []Repository{}, fmt.Errorf("fetching repositories of %s: %w", org, err)
+		}
+
+		for _, item := range result {
+			if f.matchesListing(item) {
+				repos = append(repos, newRepository(item))
+			}
+		}
+
+		if len(result) < 100 {
+			break
+		}
+
+		page++
+	}
+
+	return repos, nil
+}
+
+// listable reports whether every search qualifier of f can be checked against a repository
+// listing, so that no search is needed.
+func (f Filter) listable() bool {
+	return f.Query == "" && len(f.Properties) == 0
+}
+
+// matchesListing reports whether item, a repository from a listing API, is not archived and
+// matches the language, topics, visibility, fork and pushed since qualifiers of f.
+func (f Filter) matchesListing(item map[string]any) bool {
+	if archived, _ := item["archived"].(bool); archived {
+		return false
+	}
+
+	language, _ := item["language"].(string)
+	if f.Language != "" && !strings.EqualFold(language, f.Language) {
+		return false
+	}
+
+	topics, _ := item["topics"].([]any)
+	for _, want := range f.Topics {
+		found := false
+		for _, topic := range topics {
+			if topic, _ := topic.(string); strings.EqualFold(topic, want) {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if visibility, _ := item["visibility"].(string); f.Visibility != "" && visibility != f.Visibility {
+		return false
+	}
+
+	fork, _ := item["fork"].(bool)
+	switch f.Fork {
+	case "include":
+	case "only":
+		if !fork {
+			return false
+		}
+	default:
+		if fork {
+			return false
+		}
+	}
+
+	if f.PushedSince != "" {
+		since, _ := time.Parse(time.DateOnly, f.PushedSince)
+		pushedAt, _ := item["pushed_at"].(string)
+		pushed, err := time.Parse(time.RFC3339, pushedAt)
+		if err != nil || pushed.Before(since) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,64 @@
+package repo
+
+import "testing"
+
+func TestOwnerQualifier(t *testing.T) {
+	if got := ownerQualifier("octo-org", true); got != "org:octo-org" {
+		t.Errorf("organization: got %q", got)
+	}
+	if got := ownerQualifier("octocat", false); got != "user:octocat" {
+		t.Errorf("user: got %q", got)
+	}
+}
+
+func TestFilterListable(t *testing.T) {
+	if !(Filter{Language: "go", Topics: []string{"payments"}, CanPush: true}).listable() {
+		t.Error("qualifier filters should be listable")
+	}
+	if (Filter{Query: "svc"}).listable() {
+		t.Error("a free text query needs search")
+	}
+	if (Filter{Properties: []string{"tier=critical"}}).listable() {
+		t.Error("custom properties need search")
+	}
+}
+
+func TestFilterMatchesListing(t *testing.T) {
+	item := map[string]any{
+		"language":   "Go",
+		"topics":     []any{"payments", "backend"},
+		"visibility": "private",
+		"fork":       false,
+		"archived":   false,
+		"pushed_at":  "2024-03-01T10:00:00Z",
+	}
+
+	for name, tc := range map[string]struct {
+		f    Filter
+		item func(map[string]any)
+		want bool
+	}{
+		"no filter":        {Filter{}, nil, true},
+		"matching":         {Filter{Language: "go", Topics: []string{"payments"}, Visibility: "private", PushedSince: "2024-02-01"}, nil, true},
+		"other language":   {Filter{Language: "rust"}, nil, false},
+		"missing topic":    {Filter{Topics: []string{"payments", "frontend"}}, nil, false},
+		"other visibility": {Filter{Visibility: "public"}, nil, false},
+		"pushed too early": {Filter{PushedSince: "2024-04-01"}, nil, false},
+		"archived":         {Filter{}, func(m map[string]any) { m["archived"] = true }, false},
+		"fork excluded":    {Filter{}, func(m map[string]any) { m["fork"] = true }, false},
+		"fork included":    {Filter{Fork: "include"}, func(m map[string]any) { m["fork"] = true }, true},
+		"only forks":       {Filter{Fork: "only"}, nil, false},
+	} {
+		m := map[string]any{}
+		for k, v := range item {
+			m[k] = v
+		}
+		if tc.item != nil {
+			tc.item(m)
+		}
+
+		if got := tc.f.matchesListing(m); got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,428 @@
+package repo
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// pickerSort is the order in which the picker lists repositories.
+type pickerSort int
+
+const (
+	sortName pickerSort = iota
+	sortLanguage
+	sortPushed
+)
+
+func (s pickerSort) String() string {
+	switch s {
+	case sortLanguage:
+		return "language"
+	case sortPushed:
+		return "last push"
+	default:
+		return "name"
+	}
+}
+
+// pickerMode is what the picker's keys currently do.
+type pickerMode int
+
+const (
+	modeBrowse pickerMode = iota
+	modeFilter
+	modePattern
+)
+
+var (
+	pickerTitleStyle  = lipgloss.NewStyle().Bold(true)
+	pickerCursorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("5"))
+	pickerHelpStyle   = lipgloss.NewStyle().Faint(true)
+)
+
+const pickerHelp = "↑/↓ move • space toggle • / filter • p select pattern • a all • n none • i invert • s sort • enter confirm • esc cancel"
+
+// picker is a terminal user interface for choosing repositories from a long list. It filters
+// as the user types, shows metadata columns, sorts within each owner's group, and selects by
+// glob pattern.
+type picker struct {
+	repos    []Repository
+	selected map[string]bool
+	// visible holds the indexes into repos of the rows that pass the filter, in sort order.
+	visible []int
+	cursor  int
+	offset  int
+	height  int
+	sort    pickerSort
+	filter  string
+	mode    pickerMode
+	input   textinput.Model
+	message string
+	// owners ranks each owner, in lower case, by its first repository in repos, which is the
+	// order the owners' groups are listed in.
+	owners map[string]int
+
+	done    bool
+	aborted bool
+}
+
+func newPicker(repos []Repository, selectAll bool) *picker {
+	p := &picker{
+		repos:    repos,
+		selected: map[string]bool{},
+		height:   20,
+		input:    textinput.New(),
+		owners:   map[string]int{},
+	}
+
+	for _, r := range repos {
+		owner := strings.ToLower(r.Owner)
+		if _, ok := p.owners[owner]; !ok {
+			p.owners[owner] = len(p.owners)
+		}
+		if selectAll {
+			p.selected[r.FullName] = true
+		}
+	}
+	p.refresh()
+
+	return p
+}
+
+// refresh recomputes the visible rows from the filter and sort order, keeping the cursor in range.
+// Repositories stay grouped by owner whatever the sort order.
+func (p *picker) refresh() {
+	filter := strings.ToLower(p.filter)
+	p.visible = p.visible[:0]
+	for i, r := range p.repos {
+		if filter == "" || strings.Contains(strings.ToLower(p.name(r)+" "+r.Language), filter) {
+			p.visible = append(p.visible, i)
+		}
+	}
+
+	sort.SliceStable(p.visible, func(i, j int) bool {
+		a, b := p.repos[p.visible[i]], p.repos[p.visible[j]]
+		ownerA, ownerB := p.owners[strings.ToLower(a.Owner)], p.owners[strings.ToLower(b.Owner)]
+		if ownerA != ownerB {
+			return ownerA < ownerB
+		}
+
+		switch p.sort {
+		case sortLanguage:
+			if !strings.EqualFold(a.Language, b.Language) {
+				return strings.ToLower(a.Language) < strings.ToLower(b.Language)
+			}
+		case sortPushed:
+			if !a.PushedAt.Equal(b.PushedAt) {
+				return a.PushedAt.After(b.PushedAt)
+			}
+		}
+
+		return strings.ToLower(a.FullName) < strings.ToLower(b.FullName)
+	})
+
+	p.cursor = max(0, min(p.cursor, len(p.visible)-1))
+	p.scroll()
+}
+
+// scroll moves the window of shown rows so that it contains the cursor.
+func (p *picker) scroll() {
+	if p.cursor < p.offset {
+		p.offset = p.cursor
+	}
+	if p.cursor >= p.offset+p.height {
+		p.offset = p.cursor - p.height + 1
+	}
+	p.offset = max(0, min(p.offset, len(p.visible)-p.height))
+}
+
+// name returns how r is named in the list: with its owner when there are several, and with
+// the files that matched a code search.
+func (p *picker) name(r Repository) string {
+	if len(p.owners) > 1 {
+		return r.Owner + "/" + r.Label()
+	}
+
+	return r.Label()
+}
+
+// selectPattern selects every repository whose name or owner/name matches the glob pattern,
+// and returns how many matched.
+func (p *picker) selectPattern(pattern string) (int, error) {
+	matched := 0
+	for _, r := range p.repos {
+		byName, err := path.Match(pattern, r.Name)
+		if err != nil {
+			return 0, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+		byFullName, _ := path.Match(pattern, r.FullName)
+
+		if byName || byFullName {
+			p.selected[r.FullName] = true
+			matched++
+		}
+	}
+
+	return matched, nil
+}
+
+// setVisible selects or unselects every visible repository.
+func (p *picker) setVisible(selected bool) {
+	for _, i := range p.visible {
+		p.selected[p.repos[i].FullName] = selected
+	}
+}
+
+// invert flips the selection of every visible repository.
+func (p *picker) invert() {
+	for _, i := range p.visible {
+		name := p.repos[i].FullName
+		p.selected[name] = !p.selected[name]
+	}
+}
+
+// toggle flips the selection of the repository under the cursor.
+func (p *picker) toggle() {
+	if len(p.visible) == 0 {
+		return
+	}
+
+	name := p.repos[p.visible[p.cursor]].FullName
+	p.selected[name] = !p.selected[name]
+}
+
+// chosen returns the selected repositories in the order they were given.
+func (p *picker) chosen() []Repository {
+	repos := []Repository{}
+	for _, r := range p.repos {
+		if p.selected[r.FullName] {
+			repos = append(repos, r)
+		}
+	}
+
+	return repos
+}
+
+func (p *picker) Init() tea.Cmd {
+	return nil
+}
+
+func (p *picker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		p.height = max(3, msg.Height-6)
+		p.scroll()
+		return p, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			p.aborted = true
+			return p, tea.Quit
+		}
+
+		if p.mode != modeBrowse {
+			return p.updateInput(msg)
+		}
+
+		return p.updateBrowse(msg)
+	}
+
+	return p, nil
+}
+
+func (p *picker) updateBrowse(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	p.message = ""
+
+	switch msg.String() {
+	case "esc", "q":
+		p.aborted = true
+		return p, tea.Quit
+	case "enter":
+		p.done = true
+		return p, tea.Quit
+	case "up", "k":
+		p.cursor = max(0, p.cursor-1)
+	case "down", "j":
+		p.cursor = max(0, min(len(p.visible)-1, p.cursor+1))
+	case "pgup":
+		p.cursor = max(0, p.cursor-p.height)
+	case "pgdown":
+		p.cursor = max(0, min(len(p.visible)-1, p.cursor+p.height))
+	case "home", "g":
+		p.cursor = 0
+	case "end", "G":
+		p.cursor = max(0, len(p.visible)-1)
+	case " ", "x":
+		p.toggle()
+	case "a":
+		p.setVisible(true)
+	case "n":
+		p.setVisible(false)
+	case "i":
+		p.invert()
+	case "s":
+		p.sort = (p.sort + 1) % 3
+		p.refresh()
+	case "/":
+		p.mode = modeFilter
+		p.input.Prompt = "filter: "
+		p.input.Placeholder = ""
+		p.input.SetValue(p.filter)
+		return p, p.input.Focus()
+	case "p":
+		p.mode = modePattern
+		p.input.Prompt = "select pattern: "
+		p.input.Placeholder = "svc-*"
+		p.input.SetValue("")
+		return p, p.input.Focus()
+	}
+
+	p.scroll()
+	return p, nil
+}
+
+func (p *picker) updateInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		if p.mode == modeFilter {
+			p.filter = ""
+			p.refresh()
+		}
+		p.mode = modeBrowse
+		p.input.Blur()
+		return p, nil
+	case "enter":
+		if p.mode == modePattern && p.input.Value() != "" {
+			matched, err := p.selectPattern(p.input.Value())
+			if err != nil {
+				p.message = err.Error()
+			} else {
+				p.message = fmt.Sprintf("Selected %d repositories matching %s", matched, p.input.Value())
+			}
+		}
+		p.mode = modeBrowse
+		p.input.Blur()
+		return p, nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	if p.mode == modeFilter {
+		p.filter = p.input.Value()
+		p.refresh()
+	}
+
+	return p, cmd
+}
+
+func (p *picker) View() string {
+	if p.done || p.aborted {
+		return ""
+	}
+
+	var b strings.Builder
+
+	order := "sorted by " + p.sort.String()
+	if len(p.owners) > 1 {
+		order = "grouped by owner, " + order
+	}
+	title := fmt.Sprintf("Select Repositories to Process (%d of %d selected, %d shown, %s)",
+		len(p.chosen()), len(p.repos), len(p.visible), order)
+	b.WriteString(pickerTitleStyle.Render(title) + "\n")
+
+	switch {
+	case p.mode != modeBrowse:
+		b.WriteString(p.input.View() + "\n")
+	case p.filter != "":
+		b.WriteString("filter: " + p.filter + "\n")
+	default:
+		b.WriteString("\n")
+	}
+
+	rows := p.rows()
+	end := min(len(p.visible), p.offset+p.height)
+	for i := p.offset; i < end; i++ {
+		if i == p.cursor {
+			b.WriteString(pickerCursorStyle.Render("> "+rows[i]) + "\n")
+		} else {
+			b.WriteString("  " + rows[i] + "\n")
+		}
+	}
+	if len(p.visible) == 0 {
+		b.WriteString("  no repositories match\n")
+	}
+
+	if p.message != "" {
+		b.WriteString(p.message + "\n")
+	}
+	b.WriteString(pickerHelpStyle.Render(pickerHelp))
+
+	return b.String()
+}
+
+// rows returns the visible repositories formatted as aligned columns of name, language, last
+// push, default branch and archived or fork flags.
+func (p *picker) rows() []string {
+	cells := make([][]string, len(p.visible))
+	widths := make([]int, 5)
+	for i, index := range p.visible {
+		r := p.repos[index]
+
+		check := "[ ]"
+		if p.selected[r.FullName] {
+			check = "[x]"
+		}
+
+		pushed := ""
+		if !r.PushedAt.IsZero() {
+			pushed = r.PushedAt.Format("2006-01-02")
+		}
+
+		flags := []string{}
+		if r.Archived {
+			flags = append(flags, "archived")
+		}
+		if r.Fork {
+			flags = append(flags, "fork")
+		}
+
+		cells[i] = []string{check + " " + p.name(r), r.Language, pushed, r.DefaultBranch, strings.Join(flags, ",")}
+		for j, cell := range cells[i] {
+			widths[j] = max(widths[j], lipgloss.Width(cell))
+		}
+	}
+
+	rows := make([]string, len(cells))
+	for i, row := range cells {
+		for j := range row {
+			row[j] += strings.Repeat(" ", widths[j]-lipgloss.Width(row[j]))
+		}
+		rows[i] = strings.TrimRight(strings.Join(row, "  "), " ")
+	}
+
+	return rows
+}
+
+// SelectRepositories presents a picker and returns the chosen repositories from repos. When
+// selectAll is set every repository starts out selected.
+func SelectRepositories(repos []Repository, selectAll bool) ([]Repository, error) {
+	p := newPicker(repos, selectAll)
+
+	_, err := tea.NewProgram(p, tea.WithOutput(os.Stderr)).Run()
+	if err != nil {
+		return []Repository{}, err
+	}
+
+	if p.aborted {
+		return []Repository{}, huh.ErrUserAborted
+	}
+
+	return p.chosen(), nil
+}
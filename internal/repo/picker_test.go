@@ -0,0 +1,145 @@
+package repo
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func pickerRepos() []Repository {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+
+	return []Repository{
+		{Owner: "octo", Name: "svc-b", FullName: "octo/svc-b", Language: "Go", PushedAt: day(1)},
+		{Owner: "octo", Name: "web", FullName: "octo/web", Language: "TypeScript", PushedAt: day(3)},
+		{Owner: "octo", Name: "svc-a", FullName: "octo/svc-a", Language: "Rust", PushedAt: day(2)},
+	}
+}
+
+func visibleNames(p *picker) []string {
+	names := []string{}
+	for _, i := range p.visible {
+		names = append(names, p.repos[i].Name)
+	}
+
+	return names
+}
+
+func equalNames(a []string, b ...string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func TestPickerSortAndFilter(t *testing.T) {
+	p := newPicker(pickerRepos(), false)
+	if got := visibleNames(p); !equalNames(got, "svc-a", "svc-b", "web") {
+		t.Errorf("by name: got %v", got)
+	}
+
+	p.sort = sortPushed
+	p.refresh()
+	if got := visibleNames(p); !equalNames(got, "web", "svc-a", "svc-b") {
+		t.Errorf("by last push: got %v", got)
+	}
+
+	p.sort = sortLanguage
+	p.refresh()
+	if got := visibleNames(p); !equalNames(got, "svc-b", "svc-a", "web") {
+		t.Errorf("by language: got %v", got)
+	}
+
+	p.filter = "SVC"
+	p.refresh()
+	if got := visibleNames(p); !equalNames(got, "svc-b", "svc-a") {
+		t.Errorf("filtered: got %v", got)
+	}
+
+	p.filter = "typescript"
+	p.refresh()
+	if got := visibleNames(p); !equalNames(got, "web") {
+		t.Errorf("filtered by language: got %v", got)
+	}
+}
+
+func TestPickerGroupsByOwner(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2024, 3, d, 0, 0, 0, 0, time.UTC) }
+	p := newPicker([]Repository{
+		{Owner: "octo", Name: "api", FullName: "octo/api", Language: "Rust", PushedAt: day(1)},
+		{Owner: "hub", Name: "api", FullName: "hub/api", Language: "Go", PushedAt: day(4)},
+		{Owner: "Octo", Name: "web", FullName: "Octo/web", Language: "TypeScript", PushedAt: day(3)},
+		{Owner: "hub", Name: "cli", FullName: "hub/cli", Language: "Go", PushedAt: day(2)},
+	}, false)
+
+	for _, order := range []pickerSort{sortName, sortLanguage, sortPushed} {
+		p.sort = order
+		p.refresh()
+
+		owners := []string{}
+		for _, i := range p.visible {
+			owners = append(owners, strings.ToLower(p.repos[i].Owner))
+		}
+		if !equalNames(owners, "octo", "octo", "hub", "hub") {
+			t.Errorf("sorted by %s: owners in order %v, want octo's repositories then hub's", order, owners)
+		}
+	}
+
+	p.sort = sortPushed
+	p.refresh()
+	if got := visibleNames(p); !equalNames(got, "web", "api", "api", "cli") {
+		t.Errorf("by last push within owners: got %v", got)
+	}
+}
+
+func TestPickerSelection(t *testing.T) {
+	p := newPicker(pickerRepos(), false)
+
+	matched, err := p.selectPattern("svc-*")
+	if err != nil {
+		t.Fatalf("selectPattern: %v", err)
+	}
+	if matched != 2 {
+		t.Errorf("matched %d, want 2", matched)
+	}
+
+	// chosen keeps the order repositories were given in.
+	if got := repoNamesOf(p.chosen()); !equalNames(got, "svc-b", "svc-a") {
+		t.Errorf("after pattern: got %v", got)
+	}
+
+	p.invert()
+	if got := repoNamesOf(p.chosen()); !equalNames(got, "web") {
+		t.Errorf("after invert: got %v", got)
+	}
+
+	p.filter = "svc"
+	p.refresh()
+	p.setVisible(true)
+	if got := repoNamesOf(p.chosen()); len(got) != 3 {
+		t.Errorf("after selecting visible: got %v", got)
+	}
+
+	if _, err := p.selectPattern("["); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+
+	if got := newPicker(pickerRepos(), true).chosen(); len(got) != 3 {
+		t.Errorf("select all: got %d repositories", len(got))
+	}
+}
+
+func repoNamesOf(repos []Repository) []string {
+	names := []string{}
+	for _, r := range repos {
+		names = append(names, r.Name)
+	}
+
+	return names
+}
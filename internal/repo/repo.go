@@ -3,14 +3,20 @@ package repo
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/huh"
 	"github.com/cli/go-gh/v2"
 	"github.com/cli/go-gh/v2/pkg/api"
 	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/jepomeroy/gh-bulk/internal/commit"
@@ -18,131 +24,499 @@ import (
 
 type AuthUserKey string
 
-// Repository represents a GitHub repository with its name, SSH URL, and local clone state.
+// Owners returns the users and organizations whose repositories are queried, as stored in ctx
+// under AuthUserKey("auth"). The first owner is the one bare repository names belong to.
+func Owners(ctx context.Context) []string {
+	switch owners := ctx.Value(AuthUserKey("auth")).(type) {
+	case string:
+		return []string{owners}
+	case []string:
+		return owners
+	default:
+		return nil
+	}
+}
+
+// primaryOwner returns the first owner in ctx.
+func primaryOwner(ctx context.Context) string {
+	owners := Owners(ctx)
+	if len(owners) == 0 {
+		return ""
+	}
+
+	return owners[0]
+}
+
+// logMu serializes Logf so lines from concurrently processed repositories do not interleave.
+var logMu sync.Mutex
+
+// Repository represents a GitHub repository with its owner, name, SSH URL, and local clone state.
 type Repository struct {
-	Name    string
-	SSHURL  string
+	// Host is the GitHub host of the repository, empty when it is not known.
+	Host     string
+	Owner    string
+	Name     string
+	FullName string
+	SSHURL   string
+	CloneURL string
+	// Language, DefaultBranch, PushedAt, Archived and Fork describe r as it was listed or
+	// searched, and are shown when selecting repositories.
+	Language      string
+	DefaultBranch string
+	PushedAt      time.Time
+	Archived      bool
+	Fork          bool
+	// MatchedPaths holds the files that matched when r was found by code search.
+	MatchedPaths []string
+	// canPush is whether the user could push to r when it was listed or searched.
+	canPush bool
 	tmpDir  string
+	cloned  CloneOptions
 	gitRepo *git.Repository
 }
 
-// Clone clones r to tempDir using the GitHub CLI and changes the working directory to tempDir.
-func (r *Repository) Clone(tempDir string) error {
-	fmt.Printf("Cloning repository %s\n", r.Name)
+// Label returns the name of r followed by the files that matched a code search, if any.
+func (r Repository) Label() string {
+	const shown = 3
+
+	if len(r.MatchedPaths) == 0 {
+		return r.Name
+	}
+
+	paths := r.MatchedPaths
+	more := ""
+	if len(paths) > shown {
+		more = fmt.Sprintf(", +%d more", len(paths)-shown)
+		paths = paths[:shown]
+	}
+
+	return fmt.Sprintf("%s (%s%s)", r.Name, strings.Join(paths, ", "), more)
+}
+
+// Logf prints a message to stderr with every line prefixed by the repository name, so that
+// output from repositories processed concurrently stays readable and stdout is left for the report.
+func (r Repository) Logf(format string, args ...any) {
+	var b strings.Builder
+
+	message := strings.TrimRight(fmt.Sprintf(format, args...), "\n")
+	for _, line := range strings.Split(message, "\n") {
+		fmt.Fprintf(&b, "[%s] %s\n", r.FullName, line)
+	}
+
+	logMu.Lock()
+	defer logMu.Unlock()
+	fmt.Fprint(os.Stderr, b.String())
+}
+
+// ghRepo returns r as gh's --repo flag takes it, with the host when it is known so that gh
+// works against it without switching accounts.
+func (r Repository) ghRepo() string {
+	if r.Host == "" {
+		return r.FullName
+	}
+
+	return r.Host + "/" + r.FullName
+}
+
+// Dir returns the directory r is cloned into.
+func (r Repository) Dir() string {
+	return r.tmpDir
+}
 
-	_, stdErr, err := gh.Exec("repo", "clone", r.SSHURL, tempDir)
+// Clone clones r to tempDir using the GitHub CLI, or git for a mirror, as opts describes.
+func (r *Repository) Clone(tempDir string, opts CloneOptions) error {
+	r.Logf("Cloning repository (%s)", opts.Strategy)
+
+	r.tmpDir = tempDir
+	r.cloned = opts
+
+	source, err := r.cloneSource(opts)
 	if err != nil {
-		fmt.Printf("Error cloning repository %s: %s\n", r.Name, err)
-		fmt.Printf("Output: %s\n", stdErr.String())
 		return err
 	}
 
-	r.tmpDir = tempDir
-	gitRepo, err := git.PlainOpen(tempDir)
+	if opts.Strategy == CloneMirror {
+		err = r.git("", append([]string{"clone"}, append(opts.args(), source, tempDir)...)...)
+		if err == nil {
+			err = r.git(tempDir, "remote", "set-url", "origin", opts.Transport.url(*r))
+		}
+	} else {
+		args := append([]string{"repo", "clone", source, tempDir, "--"}, opts.args()...)
+		_, stdErr, ghErr := gh.Exec(args...)
+		if ghErr != nil {
+			err = fmt.Errorf("%w: %s", ghErr, strings.TrimSpace(stdErr.String()))
+		}
+	}
 	if err != nil {
-		fmt.Println(err)
 		return err
 	}
 
-	r.gitRepo = gitRepo
+	if len(opts.SparsePaths) > 0 {
+		err = r.sparseCheckout(tempDir, opts.SparsePaths)
+		if err != nil {
+			return err
+		}
+	}
 
-	// Shell commands executed via Execute run relative to the cloned repository.
-	err = os.Chdir(r.tmpDir)
+	gitRepo, err := git.PlainOpen(tempDir)
 	if err != nil {
-		fmt.Printf("Error changing directory to %s: %s\n", r.tmpDir, err)
 		return err
 	}
 
+	r.gitRepo = gitRepo
+
 	return nil
 }
 
 // Clean removes the cloned repository from the temporary directory.
 func (r *Repository) Clean() error {
-	fmt.Printf("Cleaning up repository %s\n", r.Name)
+	r.Logf("Cleaning up repository")
 
 	err := os.RemoveAll(r.tmpDir)
 	if err != nil {
-		fmt.Printf("Error cleaning up repository %s: %s\n", r.Name, err)
 		return err
 	}
 
 	return nil
 }
 
-// CreateBranch creates and checks out a new branch named by commit.BranchName.
+// Base returns the branch commit starts from in r: commit.BaseBranch when it is set, and
+// otherwise the default branch of r.
+func (r Repository) Base(commit commit.Commit) string {
+	if commit.BaseBranch != "" {
+		return commit.BaseBranch
+	}
+
+	return r.DefaultBranch
+}
+
+// CreateBranch creates and checks out a new branch named by commit.BranchName, starting from
+// the base branch on origin.
 func (r Repository) CreateBranch(commit commit.Commit) error {
-	println("Committing and pushing changes")
+	base := r.Base(commit)
+	r.Logf("Creating branch %s from %s", commit.BranchName, base)
+
+	// The clone checks out the default branch, so without a known base HEAD is used.
+	if base == "" {
+		return r.checkout(commit.BranchName, plumbing.ZeroHash, "HEAD")
+	}
+
+	baseRef, err := r.gitRepo.Reference(plumbing.NewRemoteReferenceName("origin", base), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return fmt.Errorf("base branch %s not found in %s", base, r.FullName)
+	}
+	if err != nil {
+		return err
+	}
+
+	return r.checkout(commit.BranchName, baseRef.Hash(), "origin/"+base)
+}
+
+// checkout creates branch at start, named startName for git, and checks it out, discarding
+// any changes in the worktree.
+func (r Repository) checkout(branch string, start plumbing.Hash, startName string) error {
+	if r.cloned.usesGitCLI() {
+		return r.git(r.tmpDir, "checkout", "--force", "-B", branch, startName)
+	}
 
 	w, err := r.gitRepo.Worktree()
 	if err != nil {
-		fmt.Println("Failed to get worktree:", err)
 		return err
 	}
 
-	newBranch := plumbing.NewBranchReferenceName(commit.BranchName)
-	err = w.Checkout(&git.CheckoutOptions{
-		Branch: newBranch,
+	return w.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Hash:   start,
 		Create: true,
 		Force:  true,
 	})
+}
+
+// CheckoutBranch checks out commit.BranchName from origin when it exists there, and otherwise
+// creates it like CreateBranch. It reports whether the branch already existed.
+func (r Repository) CheckoutBranch(commit commit.Commit) (bool, error) {
+	remoteRef, err := r.gitRepo.Reference(plumbing.NewRemoteReferenceName("origin", commit.BranchName), true)
+	if errors.Is(err, plumbing.ErrReferenceNotFound) {
+		return false, r.CreateBranch(commit)
+	}
 	if err != nil {
-		fmt.Println("Failed to checkout new branch:", err)
-		return err
+		return false, err
 	}
 
-	return nil
+	r.Logf("Checking out existing branch %s", commit.BranchName)
+
+	err = r.checkout(commit.BranchName, remoteRef.Hash(), "origin/"+commit.BranchName)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
 }
 
-// CommitAndPush stages all changes, commits with commit.CommitMessage, and pushes to origin.
-func (r Repository) CommitAndPush(commit commit.Commit) error {
+// HasChanges reports whether the worktree differs from HEAD, counting untracked files.
+func (r Repository) HasChanges() (bool, error) {
 	w, err := r.gitRepo.Worktree()
 	if err != nil {
-		fmt.Println("Failed to get worktree:", err)
+		return false, err
+	}
+
+	status, err := w.Status()
+	if err != nil {
+		return false, err
+	}
+
+	return !status.IsClean(), nil
+}
+
+// Diff returns a unified diff of the worktree against HEAD, including untracked files.
+func (r Repository) Diff() (string, error) {
+	// Mark untracked files as intended to add so git diff shows their contents.
+	err := r.git(r.tmpDir, "add", "--intent-to-add", "--all")
+	if err != nil {
+		return "", err
+	}
+
+	return r.gitOutput(r.tmpDir, "diff", "--no-ext-diff")
+}
+
+// baseRef returns the remote branch commit starts from, for git commands.
+func (r Repository) baseRef(commit commit.Commit) string {
+	if base := r.Base(commit); base != "" {
+		return "origin/" + base
+	}
+
+	return "origin/HEAD"
+}
+
+// DiffStat returns git's summary of the files changed in the worktree since the base branch of
+// commit, which after committing is what its pull request changes.
+func (r Repository) DiffStat(commit commit.Commit) (string, error) {
+	// The git CLI fetches the contents a blobless clone is missing.
+	out, err := r.gitOutput(r.tmpDir, "diff", "--no-ext-diff", "--stat", r.baseRef(commit))
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimRight(out, "\n"), nil
+}
+
+// CommitAndPush stages all changes, commits with commit.CommitMessage as identity, and pushes
+// commit.BranchName to origin, replacing the remote branch when force is set.
+func (r Repository) CommitAndPush(commit commit.Commit, identity Identity, force bool) error {
+	r.Logf("Committing and pushing changes as %s <%s>", identity.Name, identity.Email)
+
+	err := r.commit(commit.CommitMessage, identity)
+	if err != nil {
 		return err
 	}
 
-	_, err = w.Add(".")
+	branch := plumbing.NewBranchReferenceName(commit.BranchName)
+	if r.cloned.usesGitCLI() {
+		args := []string{"push", "origin", branch.String() + ":" + branch.String()}
+		if force {
+			args = append(args, "--force")
+		}
+
+		err = r.git(r.tmpDir, args...)
+	} else {
+		err = r.push(branch, force)
+	}
 	if err != nil {
-		fmt.Println("Error added in changes for commit:", err)
 		return err
 	}
 
-	_, err = w.Commit(commit.CommitMessage, &git.CommitOptions{Author: &object.Signature{
-		Name: "GH Bulk Extension",
-		When: time.Now(),
-	}})
+	r.Logf("Branch %s pushed successfully!", commit.BranchName)
+	return nil
+}
+
+// commit stages all changes and commits them with message as identity. Signed commits are made
+// with git, which can reach the user's GPG agent or SSH signing key.
+func (r Repository) commit(message string, identity Identity) error {
+	if identity.Signing != "" {
+		err := r.git(r.tmpDir, "add", "--all")
+		if err != nil {
+			return err
+		}
+
+		return r.git(r.tmpDir, identity.commitArgs(message)...)
+	}
+
+	w, err := r.gitRepo.Worktree()
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Add(".")
 	if err != nil {
-		fmt.Println("Failed to commit changes:", err)
 		return err
 	}
 
+	now := time.Now()
+	committerName, committerEmail := identity.Committer()
+	_, err = w.Commit(message, &git.CommitOptions{
+		Author:    &object.Signature{Name: identity.Name, Email: identity.Email, When: now},
+		Committer: &object.Signature{Name: committerName, Email: committerEmail, When: now},
+	})
+
+	return err
+}
+
+// push pushes branch to origin with go-git.
+func (r Repository) push(branch plumbing.ReferenceName, force bool) error {
 	pushOptions := &git.PushOptions{
 		RemoteName: "origin",
+		RefSpecs:   []config.RefSpec{config.RefSpec(branch + ":" + branch)},
+		Force:      force,
+		Auth:       r.cloned.Transport.auth(),
 	}
-	err = r.gitRepo.Push(pushOptions)
+
+	return r.gitRepo.Push(pushOptions)
+}
+
+// CreatePR opens a pull request from commit.BranchName against the base branch with the
+// commit's title, body, reviewers, assignees, labels, milestone and draft status, and returns
+// its URL.
+func (r Repository) CreatePR(commit commit.Commit) (string, error) {
+	args := []string{"pr", "create",
+		"--repo", r.ghRepo(),
+		"--head", commit.BranchName,
+		"--title", commit.PullRequestTitle,
+		"--body", commit.Body(),
+	}
+	if base := r.Base(commit); base != "" {
+		args = append(args, "--base", base)
+	}
+	args = append(args, prMetadataArgs(commit, "")...)
+	if commit.Draft {
+		args = append(args, "--draft")
+	}
+
+	stdOut, stdErr, err := gh.Exec(args...)
 	if err != nil {
-		fmt.Println("Failed to push branch:", err)
-		return err
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String()))
+	}
+
+	return strings.TrimSpace(stdOut.String()), nil
+}
+
+// FindPR returns the URL of the open pull request from commit.BranchName, or an empty string
+// when there is none.
+func (r Repository) FindPR(commit commit.Commit) (string, error) {
+	stdOut, stdErr, err := gh.Exec("pr", "list",
+		"--repo", r.ghRepo(),
+		"--head", commit.BranchName,
+		"--state", "open",
+		"--json", "url",
+		"--jq", ".[0].url // empty",
+	)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String()))
+	}
+
+	return strings.TrimSpace(stdOut.String()), nil
+}
+
+// EditPR replaces the title and body of the pull request at url with the commit's title and
+// body, retargets it when commit names a base branch, and adds the commit's reviewers,
+// assignees, labels and milestone.
+func (r Repository) EditPR(url string, commit commit.Commit) error {
+	args := []string{"pr", "edit", url,
+		"--title", commit.PullRequestTitle,
+		"--body", commit.Body(),
+	}
+	if commit.BaseBranch != "" {
+		args = append(args, "--base", commit.BaseBranch)
+	}
+	args = append(args, prMetadataArgs(commit, "add-")...)
+
+	_, stdErr, err := gh.Exec(args...)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String()))
+	}
+
+	return nil
+}
+
+// prMetadataArgs returns the gh pr flags that set the reviewers, assignees, labels and
+// milestone of commit. prefix is "add-" for gh pr edit, which adds to those already set.
+func prMetadataArgs(commit commit.Commit, prefix string) []string {
+	args := []string{}
+	for _, flag := range []struct {
+		name   string
+		values []string
+	}{
+		{"reviewer", commit.Reviewers},
+		{"assignee", commit.Assignees},
+		{"label", commit.Labels},
+	} {
+		for _, value := range flag.values {
+			args = append(args, "--"+prefix+flag.name, value)
+		}
+	}
+	if commit.Milestone != "" {
+		args = append(args, "--milestone", commit.Milestone)
+	}
+
+	return args
+}
+
+// MergePR merges the pull request at url using strategy, one of merge, squash or rebase,
+// and deletes its head branch when deleteBranch is set.
+func (r Repository) MergePR(url string, strategy string, deleteBranch bool) error {
+	args := []string{"pr", "merge", url, "--repo", r.ghRepo(), "--" + strategy}
+	if deleteBranch {
+		args = append(args, "--delete-branch")
+	}
+
+	_, stdErr, err := gh.Exec(args...)
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String()))
 	}
 
-	fmt.Printf("Branch %s pushed successfully!\n", commit.BranchName)
 	return nil
 }
 
-// CreatePR opens a pull request using the commit's title and message as body.
-func (r Repository) CreatePR(commit commit.Commit) error {
-	_, stdErr, err := gh.Exec("pr", "create", "--title", commit.PullRequestTitle, "--body", commit.CommitMessage)
+// ClosePR closes the pull request at url, first leaving comment on it unless comment is empty.
+func (r Repository) ClosePR(url string, comment string) error {
+	args := []string{"pr", "close", url, "--repo", r.ghRepo()}
+	if comment != "" {
+		args = append(args, "--comment", comment)
+	}
+
+	_, stdErr, err := gh.Exec(args...)
 	if err != nil {
-		fmt.Println(stdErr.String())
-		return err
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(stdErr.String()))
 	}
 
 	return nil
 }
 
+// HasRemoteBranch reports whether branch exists in r on GitHub.
+func (r Repository) HasRemoteBranch(client *api.RESTClient, branch string) (bool, error) {
+	var ref map[string]any
+	err := client.Get(fmt.Sprintf("repos/%s/git/ref/heads/%s", r.FullName, branch), &ref)
+
+	var httpErr *api.HTTPError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// DeleteRemoteBranch deletes branch from r on GitHub.
+func (r Repository) DeleteRemoteBranch(client *api.RESTClient, branch string) error {
+	return client.Delete(fmt.Sprintf("repos/%s/git/refs/heads/%s", r.FullName, branch), nil)
+}
+
 // FilterReposOptions prompts for a search filter and returns matching non-archived repositories.
 func FilterReposOptions(client *api.RESTClient, ctx context.Context) ([]Repository, error) {
-	var searchQuery string
+	var f Filter
+	var topics string
+	var hasFiles string
 
 	form := huh.NewForm(
 		huh.NewGroup(
@@ -150,7 +524,57 @@ func FilterReposOptions(client *api.RESTClient, ctx context.Context) ([]Reposito
 				Title("Search").
 				Prompt("filter: ").
 				Description("Empty query will return all repositories").
-				Value(&searchQuery),
+				Value(&f.Query),
+			huh.NewInput().
+				Title("Code search").
+				Prompt("code: ").
+				Description("Select repositories containing matching code instead, e.g. \"example.com/old-module\" language:go").
+				Value(&f.CodeQuery),
+			huh.NewInput().
+				Title("Language").
+				Prompt("language: ").
+				Value(&f.Language),
+			huh.NewInput().
+				Title("Topics").
+				Prompt("topics: ").
+				Description("Comma separated, every topic must match").
+				Value(&topics),
+			huh.NewSelect[string]().
+				Title("Visibility").
+				Options(
+					huh.NewOption("Any", ""),
+					huh.NewOption("Public", "public"),
+					huh.NewOption("Private", "private"),
+					huh.NewOption("Internal", "internal"),
+				).
+				Value(&f.Visibility),
+			huh.NewSelect[string]().
+				Title("Forks").
+				Options(
+					huh.NewOption("Exclude forks", ""),
+					huh.NewOption("Include forks", "include"),
+					huh.NewOption("Only forks", "only"),
+				).
+				Value(&f.Fork),
+			huh.NewInput().
+				Title("Pushed since").
+				Prompt("date: ").
+				Placeholder("2024-01-31").
+				Value(&f.PushedSince).
+				Validate(func(s string) error { return Filter{PushedSince: s}.Validate() }),
+			huh.NewConfirm().
+				Title("Only repositories you can push to").
+				Value(&f.CanPush),
+			huh.NewInput().
+				Title("Name regex").
+				Prompt("regex: ").
+				Value(&f.NameRegex).
+				Validate(func(s string) error { return Filter{NameRegex: s}.Validate() }),
+			huh.NewInput().
+				Title("Has files").
+				Prompt("paths: ").
+				Description("Comma separated, e.g. go.mod,.github/workflows/ci.yml").
+				Value(&hasFiles),
 		),
 	).WithTheme(huh.ThemeCatppuccin())
 
@@ -159,33 +583,54 @@ func FilterReposOptions(client *api.RESTClient, ctx context.Context) ([]Reposito
 		return []Repository{}, err
 	}
 
-	fmt.Println("Fetching repositories...")
+	f.Topics = splitList(topics)
+	f.HasFiles = splitList(hasFiles)
+
+	return FindRepositories(client, ctx, f)
+}
+
+// splitList splits a comma separated list, dropping empty entries.
+func splitList(s string) []string {
+	items := []string{}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+// repoSearchLimit is the most results the repository search API returns for a query.
+const repoSearchLimit = 1000
+
+func searchRepositories(client *api.RESTClient, searchQuery string, qualifiers string) ([]Repository, error) {
+	fmt.Fprintln(os.Stderr, "Fetching repositories...")
+	query := url.QueryEscape(strings.TrimSpace(fmt.Sprintf("%s %s archived:false", searchQuery, qualifiers)))
 	repos := []Repository{}
 	page := 1
 
 	for {
-		user := ctx.Value(AuthUserKey("auth"))
-		queryParams := fmt.Sprintf("%s+user:%s+archived:false&page=%d&sort=name&order=asc", searchQuery, user, page)
-
-		var result map[string]any
-		err := client.Get("search/repositories?q="+queryParams, &result)
+		var result struct {
+			TotalCount int              `json:"total_count"`
+			Items      []map[string]any `json:"items"`
+		}
+		err := client.Get(fmt.Sprintf("search/repositories?q=%s&per_page=100&page=%d&sort=name&order=asc", query, page), &result)
 		if err != nil {
-			fmt.Println("Error fetching repositories:", err)
-			return []Repository{}, err
+			return []Repository{}, fmt.Errorf("searching repositories: %w", err)
 		}
 
-		if items, ok := result["items"].([]any); ok {
-			for _, item := range items {
-				if repo, ok := item.(map[string]any); ok {
-					name := repo["name"].(string)
-					sshURL := repo["ssh_url"].(string)
-					repos = append(repos, Repository{Name: name, SSHURL: sshURL})
-				}
-			}
+		for _, item := range result.Items {
+			repos = append(repos, newRepository(item))
+		}
+
+		if len(result.Items) == 0 || len(repos) >= result.TotalCount {
+			break
 		}
 
-		totalCount := int(result["total_count"].(float64))
-		if totalCount == len(repos) {
+		if len(repos) >= repoSearchLimit {
+			fmt.Fprintf(os.Stderr, "Search matched %d repositories but only returns the first %d, narrow the query\n", result.TotalCount, repoSearchLimit)
 			break
 		}
 
@@ -195,45 +640,77 @@ func FilterReposOptions(client *api.RESTClient, ctx context.Context) ([]Reposito
 	return repos, nil
 }
 
-// SelectRepositories presents a multi-select prompt and returns the chosen repositories from repos.
-func SelectRepositories(repos []Repository) ([]Repository, error) {
-	var selections []string
-	customKeyMap := huh.NewDefaultKeyMap()
+// GetRepositories looks up each of names, given as owner/name or as a bare name owned by the first owner.
+func GetRepositories(client *api.RESTClient, ctx context.Context, names []string) ([]Repository, error) {
+	user := primaryOwner(ctx)
+	repos := []Repository{}
 
-	// customize keymap help
-	customKeyMap.MultiSelect.SelectAll.SetHelp("ctrl+shift+a", "select all")
-	customKeyMap.MultiSelect.SelectNone.SetHelp("ctrl+shift+a", "select none")
+	for _, name := range names {
+		fullName := name
+		if !strings.Contains(name, "/") {
+			fullName = fmt.Sprintf("%s/%s", user, name)
+		}
 
-	repoOptions := []huh.Option[string]{}
+		var result map[string]any
+		err := client.Get("repos/"+fullName, &result)
+		if err != nil {
+			return []Repository{}, fmt.Errorf("fetching repository %s: %w", fullName, err)
+		}
 
-	for _, repo := range repos {
-		repoOptions = append(repoOptions, huh.NewOption(repo.Name, repo.Name))
+		repos = append(repos, newRepository(result))
 	}
 
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewMultiSelect[string]().
-				Title("Select Repositories to Process").
-				Options(repoOptions...).
-				Filterable(false).
-				Value(&selections).
-				Height(20),
-		),
-	).WithKeyMap(customKeyMap).WithTheme(huh.ThemeCatppuccin())
+	return repos, nil
+}
 
-	err := form.Run()
-	if err != nil {
-		return []Repository{}, err
+func newRepository(repo map[string]any) Repository {
+	name, _ := repo["name"].(string)
+	fullName, _ := repo["full_name"].(string)
+	sshURL, _ := repo["ssh_url"].(string)
+	cloneURL, _ := repo["clone_url"].(string)
+
+	// Code search results carry only the web URL, which the clone URLs are derived from.
+	var host string
+	if htmlURL, _ := repo["html_url"].(string); htmlURL != "" {
+		if u, err := url.Parse(htmlURL); err == nil {
+			host = u.Host
+		}
+		if cloneURL == "" {
+			cloneURL = htmlURL + ".git"
+		}
+		if sshURL == "" && host != "" {
+			sshURL = fmt.Sprintf("git@%s:%s.git", host, fullName)
+		}
 	}
 
-	selectedRepos := []Repository{}
-	for _, repo := range repos {
-		for _, selection := range selections {
-			if repo.Name == selection {
-				selectedRepos = append(selectedRepos, repo)
-			}
-		}
+	ownerInfo, _ := repo["owner"].(map[string]any)
+	owner, _ := ownerInfo["login"].(string)
+	if owner == "" {
+		owner, _, _ = strings.Cut(fullName, "/")
 	}
 
-	return selectedRepos, nil
+	permissions, _ := repo["permissions"].(map[string]any)
+	canPush, _ := permissions["push"].(bool)
+
+	language, _ := repo["language"].(string)
+	defaultBranch, _ := repo["default_branch"].(string)
+	pushedAt, _ := repo["pushed_at"].(string)
+	pushed, _ := time.Parse(time.RFC3339, pushedAt)
+	archived, _ := repo["archived"].(bool)
+	fork, _ := repo["fork"].(bool)
+
+	return Repository{
+		Host:          host,
+		Owner:         owner,
+		Name:          name,
+		FullName:      fullName,
+		SSHURL:        sshURL,
+		CloneURL:      cloneURL,
+		Language:      language,
+		DefaultBranch: defaultBranch,
+		PushedAt:      pushed,
+		Archived:      archived,
+		Fork:          fork,
+		canPush:       canPush,
+	}
 }
@@ -0,0 +1,300 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/jepomeroy/gh-bulk/internal/commit"
+)
+
+func TestOwners(t *testing.T) {
+	tests := map[string]struct {
+		value any
+		want  []string
+	}{
+		"single owner":   {value: "octo", want: []string{"octo"}},
+		"several owners": {value: []string{"octo", "hub"}, want: []string{"octo", "hub"}},
+		"no owner":       {value: nil, want: nil},
+	}
+
+	for name, tc := range tests {
+		ctx := context.WithValue(context.Background(), AuthUserKey("auth"), tc.value)
+
+		got := Owners(ctx)
+		if len(got) != len(tc.want) {
+			t.Fatalf("%s: got %v, want %v", name, got, tc.want)
+		}
+		for i := range got {
+			if got[i] != tc.want[i] {
+				t.Errorf("%s: got %v, want %v", name, got, tc.want)
+			}
+		}
+	}
+
+	ctx := context.WithValue(context.Background(), AuthUserKey("auth"), []string{"octo", "hub"})
+	if got := primaryOwner(ctx); got != "octo" {
+		t.Errorf("primaryOwner: got %q", got)
+	}
+}
+
+func TestRepositoryBase(t *testing.T) {
+	r := Repository{DefaultBranch: "main"}
+
+	if got := r.Base(commit.Commit{}); got != "main" {
+		t.Errorf("default: got %q, want main", got)
+	}
+	if got := r.Base(commit.Commit{BaseBranch: "release/2.x"}); got != "release/2.x" {
+		t.Errorf("explicit base: got %q, want release/2.x", got)
+	}
+}
+
+func TestPRMetadataArgs(t *testing.T) {
+	c := commit.Commit{
+		Reviewers: []string{"alice", "octo/platform"},
+		Assignees: []string{"@me"},
+		Labels:    []string{"dependencies"},
+		Milestone: "Q4",
+	}
+
+	want := []string{
+		"--reviewer", "alice", "--reviewer", "octo/platform",
+		"--assignee", "@me", "--label", "dependencies", "--milestone", "Q4",
+	}
+	if got := prMetadataArgs(c, ""); !slices.Equal(got, want) {
+		t.Errorf("create: got %v, want %v", got, want)
+	}
+
+	if got := prMetadataArgs(commit.Commit{Labels: []string{"bulk"}}, "add-"); !slices.Equal(got, []string{"--add-label", "bulk"}) {
+		t.Errorf("edit: got %v", got)
+	}
+}
+
+// initRepository returns a Repository for a new git repository in a temporary directory with
+// one committed file, README.md.
+func initRepository(t *testing.T) Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	gitRepo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte("# api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w, err := gitRepo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("README.md"); err != nil {
+		t.Fatal(err)
+	}
+
+	signature := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	if _, err := w.Commit("Initial commit", &git.CommitOptions{Author: signature}); err != nil {
+		t.Fatal(err)
+	}
+
+	return Repository{Name: "api", FullName: "octo/api", tmpDir: dir, gitRepo: gitRepo}
+}
+
+func TestHasChanges(t *testing.T) {
+	for name, tc := range map[string]struct {
+		change func(dir string) error
+		want   bool
+	}{
+		"clean": {func(dir string) error { return nil }, false},
+		"modified": {func(dir string) error {
+			return os.WriteFile(filepath.Join(dir, "README.md"), []byte("# api v2\n"), 0o644)
+		}, true},
+		"untracked": {func(dir string) error {
+			return os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module api\n"), 0o644)
+		}, true},
+	} {
+		r := initRepository(t)
+		if err := tc.change(r.Dir()); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := r.HasChanges()
+		if err != nil {
+			t.Fatalf("%s: HasChanges: %v", name, err)
+		}
+		if got != tc.want {
+			t.Errorf("%s: got %v, want %v", name, got, tc.want)
+		}
+	}
+}
+
+// cloneRepository returns a Repository cloned from origin, with master as its default branch.
+func cloneRepository(t *testing.T, origin Repository) Repository {
+	t.Helper()
+
+	dir := t.TempDir()
+	gitRepo, err := git.PlainClone(dir, false, &git.CloneOptions{URL: origin.Dir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return Repository{Name: "api", FullName: "octo/api", DefaultBranch: "master", tmpDir: dir, gitRepo: gitRepo}
+}
+
+func TestCheckoutBranch(t *testing.T) {
+	origin := initRepository(t)
+	master, err := origin.gitRepo.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An earlier run left fix/deps on origin with a commit of its own.
+	w, err := origin.gitRepo.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("fix/deps"), Create: true}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(origin.Dir(), "go.mod"), []byte("module api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Add("go.mod"); err != nil {
+		t.Fatal(err)
+	}
+	signature := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	fixDeps, err := w.Commit("Add go.mod", &git.CommitOptions{Author: signature})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Branch: master.Name()}); err != nil {
+		t.Fatal(err)
+	}
+
+	for branch, tc := range map[string]struct {
+		want     plumbing.Hash
+		existing bool
+	}{
+		"fix/deps":  {fixDeps, true},
+		"fix/other": {master.Hash(), false},
+	} {
+		r := cloneRepository(t, origin)
+
+		existing, err := r.CheckoutBranch(commit.Commit{BranchName: branch})
+		if err != nil {
+			t.Fatalf("%s: CheckoutBranch: %v", branch, err)
+		}
+		if existing != tc.existing {
+			t.Errorf("%s: existing = %v, want %v", branch, existing, tc.existing)
+		}
+
+		head, err := r.gitRepo.Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if head.Name() != plumbing.NewBranchReferenceName(branch) || head.Hash() != tc.want {
+			t.Errorf("%s: HEAD is %s at %s, want %s", branch, head.Name(), head.Hash(), tc.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	r := initRepository(t)
+	if err := os.WriteFile(filepath.Join(r.Dir(), "go.mod"), []byte("module api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := r.Diff()
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+
+	for _, want := range []string{"+++ b/go.mod", "+module api"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("diff missing %q:\n%s", want, diff)
+		}
+	}
+}
+
+// bareOrigin returns a Repository whose SSH URL is a bare repository in a temporary directory,
+// with one commit on main, and the path of that repository.
+func bareOrigin(t *testing.T) (Repository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git("init", "--quiet", "--bare", "--initial-branch=main", "origin.git")
+	git("init", "--quiet", "--initial-branch=main", "work")
+	if err := os.WriteFile(filepath.Join(dir, "work", "README.md"), []byte("# api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("-C", "work", "add", "README.md")
+	git("-C", "work", "commit", "--quiet", "--message", "Initial commit")
+	git("-C", "work", "push", "--quiet", "../origin.git", "main")
+
+	origin := filepath.Join(dir, "origin.git")
+	r := Repository{
+		Owner:         "octo",
+		Name:          "api",
+		FullName:      "octo/api",
+		SSHURL:        "file://" + origin,
+		DefaultBranch: "main",
+	}
+
+	return r, origin
+}
+
+func TestCloneMirror(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	r, origin := bareOrigin(t)
+
+	if err := r.Clone(filepath.Join(t.TempDir(), "api"), CloneOptions{Strategy: CloneMirror}); err != nil {
+		t.Fatalf("Clone: %v", err)
+	}
+
+	c := commit.Commit{BranchName: "fix/deps", CommitMessage: "Add go.mod"}
+	if err := r.CreateBranch(c); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(r.Dir(), "go.mod"), []byte("module api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.CommitAndPush(c, Identity{Name: "Octo Cat", Email: "octocat@example.com"}, false); err != nil {
+		t.Fatalf("CommitAndPush: %v", err)
+	}
+
+	out, err := exec.Command("git", "-C", origin, "log", "--format=%s <%ae>", "-1", "fix/deps").Output()
+	if err != nil {
+		t.Fatalf("branch not pushed to origin: %v", err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "Add go.mod <octocat@example.com>" {
+		t.Errorf("origin fix/deps: got %q", got)
+	}
+
+	// The clone's origin is the repository, not the mirror it was cloned from.
+	url, err := r.gitOutput(r.Dir(), "remote", "get-url", "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(url); got != r.SSHURL {
+		t.Errorf("origin URL: got %q, want %q", got, r.SSHURL)
+	}
+}
@@ -0,0 +1,93 @@
+package repo
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+func TestParseProtocol(t *testing.T) {
+	for in, want := range map[string]Protocol{"": ProtocolSSH, "ssh": ProtocolSSH, "https": ProtocolHTTPS} {
+		got, err := ParseProtocol(in)
+		if err != nil || got != want {
+			t.Errorf("ParseProtocol(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseProtocol("git"); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
+
+func TestTransport(t *testing.T) {
+	r := Repository{SSHURL: "git@github.com:octo/api.git", CloneURL: "https://github.com/octo/api.git"}
+
+	ssh := Transport{Protocol: ProtocolSSH}
+	if got := ssh.url(r); got != r.SSHURL {
+		t.Errorf("ssh url: got %q", got)
+	}
+	if ssh.auth() != nil || ssh.env("github.com") != nil {
+		t.Error("ssh should leave credentials to the SSH agent")
+	}
+
+	https := Transport{Protocol: ProtocolHTTPS, Token: "gho_secret"}
+	if got := https.url(r); got != r.CloneURL {
+		t.Errorf("https url: got %q", got)
+	}
+
+	basic, ok := https.auth().(*http.BasicAuth)
+	if !ok || basic.Password != "gho_secret" {
+		t.Errorf("https auth: got %#v", https.auth())
+	}
+
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	env := strings.Join(https.env("GHE.example.com"), "\n")
+	want := base64.StdEncoding.EncodeToString([]byte("x-access-token:gho_secret"))
+	for _, line := range []string{
+		"GIT_CONFIG_COUNT=1",
+		"GIT_CONFIG_KEY_0=http.https://ghe.example.com/.extraHeader",
+		"GIT_CONFIG_VALUE_0=Authorization: Basic " + want,
+	} {
+		if !strings.Contains(env, line) {
+			t.Errorf("https env missing %q:\n%s", line, env)
+		}
+	}
+
+	// Config already given in the environment is kept, and the header is added after it.
+	t.Setenv("GIT_CONFIG_COUNT", "2")
+	env = strings.Join(https.env(""), "\n")
+	for _, line := range []string{
+		"GIT_CONFIG_COUNT=3",
+		"GIT_CONFIG_KEY_2=http.https://github.com/.extraHeader",
+		"GIT_CONFIG_VALUE_2=Authorization: Basic " + want,
+	} {
+		if !strings.Contains(env, line) {
+			t.Errorf("https env missing %q:\n%s", line, env)
+		}
+	}
+}
+
+func TestNewRepositoryURLs(t *testing.T) {
+	// Code search results have no clone URLs.
+	r := newRepository(map[string]any{"name": "api", "full_name": "octo/api", "html_url": "https://github.com/octo/api"})
+
+	if r.SSHURL != "git@github.com:octo/api.git" || r.CloneURL != "https://github.com/octo/api.git" {
+		t.Errorf("got %q and %q", r.SSHURL, r.CloneURL)
+	}
+}
+
+func TestNewRepositoryHost(t *testing.T) {
+	r := newRepository(map[string]any{"name": "api", "full_name": "octo/api", "html_url": "https://ghe.example.com/octo/api"})
+
+	if r.Host != "ghe.example.com" || r.SSHURL != "git@ghe.example.com:octo/api.git" {
+		t.Errorf("got host %q and %q", r.Host, r.SSHURL)
+	}
+	if got := r.ghRepo(); got != "ghe.example.com/octo/api" {
+		t.Errorf("ghRepo() = %q", got)
+	}
+	if got := (Repository{FullName: "octo/api"}).ghRepo(); got != "octo/api" {
+		t.Errorf("ghRepo() without a host = %q", got)
+	}
+}
@@ -0,0 +1,246 @@
+// Package report records the outcome of a bulk run for each repository and writes it
+// as a table, JSON, CSV, or markdown.
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+)
+
+// Status is how far a repository got through a bulk run.
+type Status string
+
+const (
+	// StatusCloned means the repository was cloned but has not finished processing.
+	StatusCloned Status = "cloned"
+	// StatusChanged means a dry run left changes in the worktree.
+	StatusChanged Status = "changed"
+	// StatusUnchanged means the command left the worktree clean.
+	StatusUnchanged Status = "unchanged"
+	// StatusFailed means a step failed; Result.Step names it.
+	StatusFailed Status = "failed"
+	// StatusPROpened means the changes were pushed and a pull request opened.
+	StatusPROpened Status = "pr opened"
+	// StatusPRUpdated means the changes were pushed to an existing pull request.
+	StatusPRUpdated Status = "pr updated"
+	// StatusReady means a pull request would be merged by a dry run.
+	StatusReady Status = "ready"
+	// StatusMerged means a pull request was merged.
+	StatusMerged Status = "merged"
+	// StatusSkipped means a pull request was left alone; Result.Err says why.
+	StatusSkipped Status = "skipped"
+	// StatusClosed means a pull request was closed and its head branch deleted.
+	StatusClosed Status = "closed"
+	// StatusBranchDeleted means a head branch without an open pull request was deleted.
+	StatusBranchDeleted Status = "branch deleted"
+)
+
+// Step names a stage of processing a repository.
+type Step string
+
+const (
+	StepSetup   Step = "setup"
+	StepClone   Step = "clone"
+	StepBranch  Step = "branch"
+	StepCommand Step = "command"
+	StepStatus  Step = "status"
+	StepDiff    Step = "diff"
+	StepPush    Step = "push"
+	StepPR      Step = "pr"
+	StepMerge   Step = "merge"
+	StepClose   Step = "close"
+	StepDelete  Step = "delete branch"
+)
+
+// Result is the outcome of processing a single repository.
+type Result struct {
+	Repository string
+	Status     Status
+	Step       Step
+	Err        error
+	PRURL      string
+	Duration   time.Duration
+}
+
+// Fail marks r as failed at step with err.
+func (r *Result) Fail(step Step, err error) {
+	r.Status = StatusFailed
+	r.Step = step
+	r.Err = err
+}
+
+// StatusText returns the status, including the failed step when there is one.
+func (r Result) StatusText() string {
+	if r.Status == StatusFailed && r.Step != "" {
+		return fmt.Sprintf("%s (%s)", r.Status, r.Step)
+	}
+
+	return string(r.Status)
+}
+
+// ErrorText returns the error message, or an empty string when r has no error.
+func (r Result) ErrorText() string {
+	if r.Err == nil {
+		return ""
+	}
+
+	return r.Err.Error()
+}
+
+type jsonResult struct {
+	Repository string  `json:"repository"`
+	Status     Status  `json:"status"`
+	Step       Step    `json:"step,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	PRURL      string  `json:"prUrl,omitempty"`
+	Duration   float64 `json:"durationSeconds"`
+}
+
+// MarshalJSON encodes r with its error as a string and its duration in seconds.
+func (r Result) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonResult{
+		Repository: r.Repository,
+		Status:     r.Status,
+		Step:       r.Step,
+		Error:      r.ErrorText(),
+		PRURL:      r.PRURL,
+		Duration:   r.Duration.Seconds(),
+	})
+}
+
+// Format is an output format for a report.
+type Format string
+
+const (
+	FormatTable    Format = "table"
+	FormatJSON     Format = "json"
+	FormatCSV      Format = "csv"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat converts a command line name into a Format.
+func ParseFormat(s string) (Format, error) {
+	switch Format(s) {
+	case FormatTable, FormatJSON, FormatCSV, FormatMarkdown:
+		return Format(s), nil
+	case "md":
+		return FormatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown output format %q, expected table, json, csv or markdown", s)
+	}
+}
+
+var header = []string{"REPOSITORY", "STATUS", "DURATION", "PR", "ERROR"}
+
+func (r Result) fields() []string {
+	return []string{
+		r.Repository,
+		r.StatusText(),
+		r.Duration.Round(100 * time.Millisecond).String(),
+		r.PRURL,
+		firstLine(r.ErrorText()),
+	}
+}
+
+// Write writes results to w in format. Tables are written as plain tab-separated
+// rows; use WriteTable to render for a terminal.
+func Write(w io.Writer, format Format, results []Result) error {
+	switch format {
+	case FormatJSON:
+		return writeJSON(w, results)
+	case FormatCSV:
+		return writeCSV(w, results)
+	case FormatMarkdown:
+		return writeMarkdown(w, results)
+	default:
+		return WriteTable(tableprinter.New(w, false, 0), results)
+	}
+}
+
+// WriteTable renders results with tp.
+func WriteTable(tp tableprinter.TablePrinter, results []Result) error {
+	tp.AddHeader(header)
+	for _, r := range results {
+		for _, field := range r.fields() {
+			tp.AddField(field)
+		}
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}
+
+func writeJSON(w io.Writer, results []Result) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(results)
+}
+
+func writeCSV(w io.Writer, results []Result) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write([]string{"repository", "status", "step", "duration_seconds", "pr_url", "error"})
+	if err != nil {
+		return err
+	}
+
+	for _, r := range results {
+		err = cw.Write([]string{
+			r.Repository,
+			string(r.Status),
+			string(r.Step),
+			fmt.Sprintf("%.1f", r.Duration.Seconds()),
+			r.PRURL,
+			r.ErrorText(),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeMarkdown(w io.Writer, results []Result) error {
+	var b strings.Builder
+
+	b.WriteString("| Repository | Status | Duration | Pull request | Error |\n")
+	b.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, r := range results {
+		fields := r.fields()
+		for i := range fields {
+			fields[i] = escapeMarkdown(fields[i])
+		}
+		fmt.Fprintf(&b, "| %s |\n", strings.Join(fields, " | "))
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// Counts returns the number of results with each status.
+func Counts(results []Result) map[Status]int {
+	counts := map[Status]int{}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+
+	return counts
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
+
+func escapeMarkdown(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
@@ -0,0 +1,97 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var results = []Result{
+	{Repository: "octo/repo-a", Status: StatusPROpened, PRURL: "https://github.com/octo/repo-a/pull/1", Duration: 2 * time.Second},
+	{Repository: "octo/repo-b", Status: StatusUnchanged, Duration: time.Second},
+	{Repository: "octo/repo-c", Status: StatusFailed, Step: StepCommand, Err: errors.New("exit status 1\nboom | bang")},
+}
+
+func TestParseFormat(t *testing.T) {
+	for in, want := range map[string]Format{
+		"table":    FormatTable,
+		"json":     FormatJSON,
+		"csv":      FormatCSV,
+		"markdown": FormatMarkdown,
+		"md":       FormatMarkdown,
+	} {
+		got, err := ParseFormat(in)
+		if err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseFormat("yaml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestWrite_json(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatJSON, results); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(got) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(got))
+	}
+	if got[0]["prUrl"] != "https://github.com/octo/repo-a/pull/1" || got[0]["durationSeconds"] != 2.0 {
+		t.Errorf("unexpected first result: %v", got[0])
+	}
+	if got[2]["step"] != "command" || !strings.Contains(got[2]["error"].(string), "boom") {
+		t.Errorf("unexpected failed result: %v", got[2])
+	}
+}
+
+func TestWrite_csv(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatCSV, results); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	got := buf.String()
+	for _, want := range []string{
+		"repository,status,step,duration_seconds,pr_url,error",
+		"octo/repo-a,pr opened,,2.0,https://github.com/octo/repo-a/pull/1,",
+		"octo/repo-c,failed,command,0.0,,\"exit status 1",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("CSV missing %q\ngot:\n%s", want, got)
+		}
+	}
+}
+
+func TestWrite_markdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, FormatMarkdown, results); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected header, separator and 3 rows, got %d lines:\n%s", len(lines), buf.String())
+	}
+	if want := "| octo/repo-c | failed (command) | 0s |  | exit status 1 |"; lines[4] != want {
+		t.Errorf("row: got %q, want %q", lines[4], want)
+	}
+}
+
+func TestCounts(t *testing.T) {
+	got := Counts(results)
+
+	if got[StatusPROpened] != 1 || got[StatusUnchanged] != 1 || got[StatusFailed] != 1 {
+		t.Errorf("unexpected counts: %v", got)
+	}
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/spf13/cobra"
+)
+
+func newListCmd() *cobra.Command {
+	var owners []string
+	var filter repo.Filter
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the repositories a run would search",
+		Long: `List the repositories matching a search query and filters. Without a query or
+filters every repository of the owner is listed.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runList(owners, filter)
+		},
+	}
+
+	addOwnerFlag(cmd, &owners)
+	addFilterFlags(cmd, &filter)
+
+	return cmd
+}
+
+func runList(owners []string, filter repo.Filter) error {
+	host := resolveHost("")
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	owners, err = resolveOwners(client, host, owners)
+	if err != nil {
+		return err
+	}
+
+	repos, err := repo.FindRepositories(client, ownerContext(owners), filter)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range repos {
+		fmt.Println(r.FullName)
+	}
+
+	return nil
+}
@@ -1,21 +1,21 @@
 // Command gh-bulk automates bulk operations across multiple GitHub repositories.
 // It clones each selected repository, runs a specified shell command, commits the
-// changes, and opens a pull request — all driven by interactive prompts.
+// changes, and opens a pull request — driven by flags, a plan file, or interactive prompts.
 package main
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"os"
-	"path"
-	"strings"
 
 	"github.com/charmbracelet/huh"
 	"github.com/cli/go-gh/v2/pkg/api"
-	"github.com/jepomeroy/gh-bulk/internal/commit"
+	"github.com/cli/go-gh/v2/pkg/auth"
+	"github.com/cli/go-gh/v2/pkg/term"
 	"github.com/jepomeroy/gh-bulk/internal/config"
-	"github.com/jepomeroy/gh-bulk/internal/execute"
 	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/spf13/cobra"
 )
 
 // UserAuth stores the authenticated GitHub user's login information.
@@ -23,202 +23,207 @@ var (
 	UserAuth Auth
 )
 
-// Auth holds the GitHub API user's login name.
+// hostname is the GitHub host given with --hostname, empty for the host gh defaults to.
+var hostname string
+
+// Auth holds the GitHub API user's login name, and the id, name and email used to author commits.
 type Auth struct {
 	Login string
+	ID    int64
+	Name  string
+	Email string
 }
 
 func main() {
-	cwd, err := os.Getwd()
+	err := newRootCmd().Execute()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+		os.Exit(1)
 	}
+}
 
-	client, err := api.DefaultRESTClient()
-	if err != nil {
-		fmt.Println("Error creating API client:", err)
-		os.Exit(0)
-		return
-	}
+func newRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "bulk",
+		Short:        "Run a command across many repositories and open pull requests",
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRun(defaultRunOptions())
+		},
+	}
+
+	cmd.PersistentFlags().StringVar(&hostname, "hostname", "", "GitHub host to operate on, such as a GitHub Enterprise Server (default: gh's default host)")
+
+	cmd.AddCommand(
+		newRunCmd(),
+		newConfigCmd(),
+		newListCmd(),
+		newStatusCmd(),
+		newResumeCmd(),
+		newMergeCmd(),
+		newAbortCmd(),
+	)
 
-	err = client.Get("user", &UserAuth)
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
-	}
+	return cmd
+}
 
-	ctx := context.Background()
+// isInteractive reports whether stdin is a terminal, which is required before showing any form.
+func isInteractive() bool {
+	return term.IsTerminal(os.Stdin)
+}
+
+// confirm asks the user to verify description before continuing, and reports whether they did.
+func confirm(title string, description string) bool {
+	var confirmed bool
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title(title).
+				Description(description).
+				Affirmative("Correct").
+				Negative("Abort").
+				Value(&confirmed),
+		),
+	)
 
-	c, err := config.LoadConfig()
+	err := form.Run()
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+		return false
 	}
 
-	if !c.HasEntry(UserAuth.Login) {
-		user, err := c.AddEntry(UserAuth.Login)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(0)
-			return
-		}
-
-		ctx = context.WithValue(ctx, repo.AuthUserKey("auth"), user)
-	} else {
-		user, err := c.GetAuthUser(UserAuth.Login)
-		if err != nil {
-			fmt.Println(err)
-			os.Exit(0)
-			return
-		}
-
-		ctx = context.WithValue(ctx, repo.AuthUserKey("auth"), user)
-	}
+	return confirmed
+}
 
-	repoList, err := repo.FilterReposOptions(client, ctx)
+// resolveHost returns the host given with --hostname, then fallback, which is the host a
+// plan or recorded run names, and then the host gh defaults to.
+func resolveHost(fallback string) string {
+	host, _ := auth.DefaultHost()
+	return cmp.Or(hostname, fallback, host)
+}
+
+// newClient returns a REST client for host, authenticated with the token gh has for it.
+func newClient(host string) (*api.RESTClient, error) {
+	client, err := api.NewRESTClient(api.ClientOptions{Host: host})
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+		return nil, fmt.Errorf("creating API client for %s: %w", host, err)
 	}
 
-	if len(repoList) == 0 {
-		fmt.Println("No repositories found")
-		os.Exit(0)
-		return
-	}
+	return client, nil
+}
 
-	repos, err := repo.SelectRepositories(repoList)
+// loadUserAuth fetches the authenticated user into UserAuth.
+func loadUserAuth(client *api.RESTClient) error {
+	return client.Get("user", &UserAuth)
+}
+
+// loadLogin fetches the current gh login on host and loads the gh-bulk config for host, once
+// for everything a command resolves from them.
+func loadLogin(client *api.RESTClient, host string) (Auth, *config.Config, error) {
+	err := loadUserAuth(client)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+		return Auth{}, nil, err
 	}
 
-	if len(repos) == 0 {
-		fmt.Println("No repositories selected")
-		os.Exit(0)
-		return
+	c, err := config.LoadConfig(host)
+	if err != nil {
+		return Auth{}, nil, err
 	}
 
-	commit, err := commit.NewCommit()
-	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+	return UserAuth, c, nil
+}
+
+// resolveOwners returns owners when any are given and otherwise the owners configured for
+// the current gh login on host, prompting to create the config entry when running interactively.
+func resolveOwners(client *api.RESTClient, host string, owners []string) ([]string, error) {
+	if len(owners) > 0 {
+		return owners, nil
 	}
 
-	command, err := execute.GetCommand()
+	user, c, err := loadLogin(client, host)
 	if err != nil {
-		fmt.Println(err)
-		os.Exit(0)
-		return
+		return nil, err
 	}
 
-	if !validate(command, commit, repos) {
-		fmt.Println("Aborting...")
-		os.Exit(0)
-		return
+	return configuredOwners(c, user)
+}
+
+// configuredOwners returns the owners configured in c for user, prompting to create the config
+// entry when running interactively.
+func configuredOwners(c *config.Config, user Auth) ([]string, error) {
+	if c.HasEntry(user.Login) {
+		return c.GetOwners(user.Login)
 	}
 
-	processRepos(cwd, repos, command, commit)
-}
-
-func processRepos(cwd string, repos []repo.Repository, command execute.Command, commit commit.Commit) {
-	for _, r := range repos {
-		tempDir := path.Join(os.TempDir(), r.Name)
-		err := r.Clone(tempDir)
-		if err != nil {
-			fmt.Println("Error cloning repository:", tempDir, err)
-			clean(cwd, r)
-			continue
-		}
-
-		err = r.CreateBranch(commit)
-		if err != nil {
-			fmt.Println("Error creating branch:", err)
-			clean(cwd, r)
-			continue
-		}
-
-		err = command.Execute()
-		if err != nil {
-			fmt.Println("Error executing command:", err)
-			clean(cwd, r)
-			continue
-		}
-
-		err = r.CommitAndPush(commit)
-		if err != nil {
-			fmt.Println("Error committing and pushing:", err)
-			clean(cwd, r)
-			continue
-		}
-
-		err = r.CreatePR(commit)
-		if err != nil {
-			fmt.Println("Error creating PR:", err)
-			clean(cwd, r)
-			continue
-		}
-
-		clean(cwd, r)
+	if !isInteractive() {
+		return nil, fmt.Errorf("no gh-bulk config for %s: pass --owner or run gh bulk config set", user.Login)
 	}
+
+	return c.AddEntry(user.Login)
 }
 
-func clean(cwd string, r repo.Repository) {
-	os.Chdir(cwd)
-	err := r.Clean()
-	if err != nil {
-		fmt.Printf("Error cleaning %s, %s\n", r.Name, err)
+// resolveIdentity returns the commit identity of entry, the config entry of user on host, with
+// the author taken from git config and then from user where it is not configured.
+func resolveIdentity(entry config.ConfigEntry, user Auth, host string) (repo.Identity, error) {
+	identity := entry.Commit.WithDefaults(repo.GitConfigIdentity())
+
+	name := user.Name
+	if name == "" {
+		name = user.Login
 	}
+	email := user.Email
+	if email == "" {
+		email = fmt.Sprintf("%d+%s@users.noreply.%s", user.ID, user.Login, host)
+	}
+	identity = identity.WithDefaults(repo.Identity{Name: name, Email: email})
+
+	return identity, identity.Validate()
 }
 
-func makeDescription(command execute.Command, commit commit.Commit, selectedRepos []repo.Repository) string {
-	var description strings.Builder
+// resolveTransport returns the transport of entry, a config entry on host, with the token gh
+// has for host when it is https.
+func resolveTransport(entry config.ConfigEntry, host string) (repo.Transport, error) {
+	protocol, err := repo.ParseProtocol(entry.Transport)
+	if err != nil {
+		return repo.Transport{}, err
+	}
 
-	fmt.Fprintf(&description, "%-20s %s\n%-20s %s\n%-20s %s\n%-20s %s\n\n",
-		"command:",
-		command.CommandValue,
-		"branch name:",
-		commit.BranchName,
-		"pull request title:",
-		commit.PullRequestTitle,
-		"commit message:",
-		commit.CommitMessage,
-	)
+	if protocol != repo.ProtocolHTTPS {
+		return repo.Transport{Protocol: protocol}, nil
+	}
 
-	description.WriteString("Repositories:\n")
-	for _, r := range selectedRepos {
-		fmt.Fprintf(&description, "  %s\n", r.Name)
+	token, _ := auth.TokenForHost(host)
+	if token == "" {
+		return repo.Transport{}, fmt.Errorf("no gh token for %s, run gh auth login --hostname %s", host, host)
 	}
 
-	return description.String()
+	return repo.Transport{Protocol: protocol, Token: token}, nil
 }
 
-func validate(command execute.Command, commit commit.Commit, selectedRepos []repo.Repository) bool {
-	var confirm bool
-	description := makeDescription(command, commit, selectedRepos)
-
-	form := huh.NewForm(
-		huh.NewGroup(
-			huh.NewConfirm().
-				Title("Verify Info").
-				Description(description).
-				Affirmative("Correct").
-				Negative("Abort").
-				Value(&confirm),
-		),
-	)
+// addOwnerFlag adds the flag that overrides the configured owners to cmd.
+func addOwnerFlag(cmd *cobra.Command, owners *[]string) {
+	cmd.Flags().StringSliceVar(owners, "owner", nil, "User or organization that owns the repositories (repeatable)")
+}
 
-	err := form.Run()
-	if err != nil {
-		return false
-	}
+// addFilterFlags adds the flags that select repositories by search query and filters to cmd.
+func addFilterFlags(cmd *cobra.Command, f *repo.Filter) {
+	flags := cmd.Flags()
+	flags.StringVar(&f.Query, "query", "", "Select repositories matching a search query")
+	flags.StringVar(&f.CodeQuery, "code-query", "", "Select repositories containing code matching a code search query")
+	flags.StringVar(&f.Language, "language", "", "Select repositories whose primary language is this")
+	flags.StringSliceVar(&f.Topics, "topic", nil, "Select repositories with this topic (repeatable)")
+	flags.StringVar(&f.Visibility, "visibility", "", "Select repositories with this visibility: public, private or internal")
+	flags.StringVar(&f.Fork, "fork", "", "Whether to exclude, include or only select forks (default exclude)")
+	flags.StringVar(&f.PushedSince, "pushed-since", "", "Select repositories pushed to on or after a date (YYYY-MM-DD)")
+	flags.BoolVar(&f.CanPush, "can-push", false, "Select only repositories you can push to")
+	flags.StringSliceVar(&f.Properties, "property", nil, "Select repositories whose custom property has a value, as name=value (repeatable)")
+	flags.StringVar(&f.Team, "team", "", "Select repositories of the organization team with this slug")
+	flags.StringVar(&f.TeamPermission, "team-permission", "", "With --team, the least permission the team must have: pull, triage, push, maintain or admin")
+	flags.StringVar(&f.NameRegex, "name-regex", "", "Select repositories whose name matches a regular expression")
+	flags.StringSliceVar(&f.HasFiles, "has-file", nil, "Select repositories containing this path (repeatable)")
+}
 
-	return confirm
+// ownerContext returns a context carrying owners for the repo package's queries.
+func ownerContext(owners []string) context.Context {
+	return context.WithValue(context.Background(), repo.AuthUserKey("auth"), owners)
 }
@@ -1,12 +1,21 @@
 package main
 
 import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
 
+	"github.com/jepomeroy/gh-bulk/internal/campaign"
 	"github.com/jepomeroy/gh-bulk/internal/commit"
+	"github.com/jepomeroy/gh-bulk/internal/config"
 	"github.com/jepomeroy/gh-bulk/internal/execute"
+	"github.com/jepomeroy/gh-bulk/internal/plan"
 	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/jepomeroy/gh-bulk/internal/report"
 )
 
 func TestMakeDescription(t *testing.T) {
@@ -17,17 +26,215 @@ func TestMakeDescription(t *testing.T) {
 		CommitMessage:    "Update go.mod and go.sum",
 	}
 	repos := []repo.Repository{
-		{Name: "repo-a"},
-		{Name: "repo-b"},
+		{Name: "repo-a", FullName: "octo/repo-a"},
+		{Name: "repo-a", FullName: "hub/repo-a"},
 	}
 
 	got := makeDescription(cmd, c, repos)
 
 	for _, want := range []string{
-		"go mod tidy", "fix/deps", "Fix dependencies", "Update go.mod and go.sum", "repo-a", "repo-b",
+		"go mod tidy", "fix/deps", "Fix dependencies", "Update go.mod and go.sum", "octo/repo-a", "hub/repo-a",
 	} {
 		if !strings.Contains(got, want) {
 			t.Errorf("description missing %q\ngot:\n%s", want, got)
 		}
 	}
 }
+
+func TestRunOptionsApply(t *testing.T) {
+	p := plan.Plan{Filter: repo.Filter{Query: "svc"}, Branch: "fix/deps", Title: "From file", Command: "true"}
+	opts := &runOptions{repos: []string{"repo-a"}, title: "From flag"}
+
+	opts.apply(&p)
+
+	if p.Query != "" {
+		t.Errorf("expected --repo to clear the query, got %q", p.Query)
+	}
+	if len(p.Repos) != 1 || p.Repos[0] != "repo-a" {
+		t.Errorf("unexpected repos: %v", p.Repos)
+	}
+	if p.Title != "From flag" {
+		t.Errorf("title: got %q, want %q", p.Title, "From flag")
+	}
+	if p.Branch != "fix/deps" {
+		t.Errorf("branch: got %q, want %q", p.Branch, "fix/deps")
+	}
+}
+
+func TestMakeSummary(t *testing.T) {
+	got := makeSummary([]report.Result{
+		{Repository: "octo/repo-a", Status: report.StatusPROpened},
+		{Repository: "octo/repo-b", Status: report.StatusPROpened},
+		{Repository: "octo/repo-c", Status: report.StatusFailed, Step: report.StepClone},
+	})
+
+	for _, want := range []string{"3 repositories", "2 pr opened", "1 failed"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("summary missing %q\ngot:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "unchanged") {
+		t.Errorf("summary should omit statuses without results\ngot:\n%s", got)
+	}
+}
+
+func TestResolveReviewers(t *testing.T) {
+	UserAuth = Auth{Login: "me"}
+	t.Cleanup(func() { UserAuth = Auth{} })
+
+	opts := processOptions{
+		commit:        commit.Commit{Reviewers: []string{"alice", "Me", "octo/platform", "Alice"}},
+		repoReviewers: plan.RepoReviewers{"web": {"bob"}},
+	}
+
+	got := resolveReviewers(repo.Repository{Owner: "octo", Name: "api"}, opts)
+	if want := []string{"alice", "octo/platform"}; !slices.Equal(got, want) {
+		t.Errorf("run reviewers: got %v, want %v", got, want)
+	}
+
+	got = resolveReviewers(repo.Repository{Owner: "octo", Name: "web"}, opts)
+	if want := []string{"bob"}; !slices.Equal(got, want) {
+		t.Errorf("listed reviewers: got %v, want %v", got, want)
+	}
+}
+
+func TestResolveIdentity(t *testing.T) {
+	// Without git config the author comes from the gh user.
+	t.Setenv("GIT_CONFIG_GLOBAL", os.DevNull)
+	t.Setenv("GIT_CONFIG_NOSYSTEM", "1")
+	t.Chdir(t.TempDir())
+
+	user := Auth{Login: "octocat", ID: 42}
+	identity, err := resolveIdentity(config.ConfigEntry{}, user, "ghe.example.com")
+	if err != nil {
+		t.Fatalf("resolveIdentity: %v", err)
+	}
+	if identity.Name != "octocat" || identity.Email != "42+octocat@users.noreply.ghe.example.com" {
+		t.Errorf("from the gh user: got %s <%s>", identity.Name, identity.Email)
+	}
+
+	entry := config.ConfigEntry{Commit: repo.Identity{Email: "bot@example.com"}}
+	identity, err = resolveIdentity(entry, user, "github.com")
+	if err != nil {
+		t.Fatalf("resolveIdentity: %v", err)
+	}
+	if identity.Name != "octocat" || identity.Email != "bot@example.com" {
+		t.Errorf("from the config entry: got %s <%s>", identity.Name, identity.Email)
+	}
+}
+
+func TestResolveTransport(t *testing.T) {
+	t.Setenv("GH_TOKEN", "secret")
+
+	transport, err := resolveTransport(config.ConfigEntry{}, "github.com")
+	if err != nil || transport != (repo.Transport{Protocol: repo.ProtocolSSH}) {
+		t.Errorf("default: got %+v, %v", transport, err)
+	}
+
+	transport, err = resolveTransport(config.ConfigEntry{Transport: "https"}, "github.com")
+	if err != nil || transport != (repo.Transport{Protocol: repo.ProtocolHTTPS, Token: "secret"}) {
+		t.Errorf("https: got %+v, %v", transport, err)
+	}
+
+	if _, err := resolveTransport(config.ConfigEntry{Transport: "ftp"}, "github.com"); err == nil {
+		t.Error("expected an error for an unknown transport")
+	}
+}
+
+func TestDefaultRunOptions(t *testing.T) {
+	opts := defaultRunOptions()
+
+	if opts.concurrency < 1 {
+		t.Errorf("concurrency: got %d, want at least 1", opts.concurrency)
+	}
+	if _, err := report.ParseFormat(opts.output); err != nil {
+		t.Errorf("output: %v", err)
+	}
+
+	// gh bulk without a subcommand behaves like gh bulk run without flags.
+	flags := newRunCmd().Flags()
+	for name, want := range map[string]string{"concurrency": strconv.Itoa(opts.concurrency), "output": opts.output} {
+		if got := flags.Lookup(name).DefValue; got != want {
+			t.Errorf("--%s default: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestPullRequestRepos(t *testing.T) {
+	repos := pullRequestRepos([]campaign.PullRequest{
+		{Repository: "octo/api", Number: 1, State: "closed"},
+		{Repository: "octo/web", Number: 2, State: "open"},
+		{Repository: "octo/api", Number: 3, State: "open"},
+	})
+
+	if got := repoNames(repos); !slices.Equal(got, []string{"octo/api", "octo/web"}) {
+		t.Errorf("got %v", got)
+	}
+}
+
+// bareOrigin returns a repository whose SSH URL is a local bare repository with one commit on
+// main, so that a mirror clone of it needs neither gh nor the network.
+func bareOrigin(t *testing.T) (repo.Repository, string) {
+	t.Helper()
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+
+		cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	git("init", "--quiet", "--bare", "--initial-branch=main", "origin.git")
+	git("init", "--quiet", "--initial-branch=main", "work")
+	if err := os.WriteFile(filepath.Join(dir, "work", "README.md"), []byte("# api\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	git("-C", "work", "add", "README.md")
+	git("-C", "work", "commit", "--quiet", "--message", "Initial commit")
+	git("-C", "work", "push", "--quiet", "../origin.git", "main")
+
+	origin := filepath.Join(dir, "origin.git")
+	r := repo.Repository{
+		Owner:         "octo",
+		Name:          "api",
+		FullName:      "octo/api",
+		SSHURL:        "file://" + origin,
+		DefaultBranch: "main",
+	}
+
+	return r, origin
+}
+
+func TestProcessRepo_dryRun(t *testing.T) {
+	t.Setenv("GH_CONFIG_DIR", t.TempDir())
+	r, origin := bareOrigin(t)
+
+	opts := newProcessOptions(plan.Plan{Branch: "fix/deps", Title: "Fix", Command: "echo tidy > go.mod", Clone: "mirror"})
+	opts.dryRun = true
+
+	result := processRepo(r, opts)
+	if result.Status != report.StatusChanged || result.PRURL != "" {
+		t.Fatalf("got status %s, PR %q and error %v, want %s without a PR", result.Status, result.PRURL, result.Err, report.StatusChanged)
+	}
+
+	// Nothing may be pushed: origin still has only main, at its first commit.
+	out, err := exec.Command("git", "--git-dir", origin, "for-each-ref", "--format=%(refname)").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "refs/heads/main" {
+		t.Errorf("origin refs: got %q, want only refs/heads/main", got)
+	}
+
+	out, err = exec.Command("git", "--git-dir", origin, "rev-list", "--count", "main").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.TrimSpace(string(out)); got != "1" {
+		t.Errorf("origin main has %s commits, want 1", got)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jepomeroy/gh-bulk/internal/campaign"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// mergeOptions holds the flags of the merge command.
+type mergeOptions struct {
+	owners       []string
+	strategy     string
+	deleteBranch bool
+	dryRun       bool
+	yes          bool
+	output       string
+}
+
+func newMergeCmd() *cobra.Command {
+	opts := &mergeOptions{}
+
+	cmd := &cobra.Command{
+		Use:   "merge <branch-or-run>",
+		Short: "Merge the approved pull requests opened from a branch",
+		Long: `Merge every open, non-draft pull request opened from a branch whose review is approved and
+whose checks pass. Pull requests that are not ready are skipped and listed in the report.
+The argument is either the branch name or the id of a recorded run.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMerge(opts, args[0])
+		},
+	}
+
+	flags := cmd.Flags()
+	addOwnerFlag(cmd, &opts.owners)
+	flags.StringVar(&opts.strategy, "strategy", "merge", "Merge strategy: merge, squash or rebase")
+	flags.BoolVar(&opts.deleteBranch, "delete-branch", false, "Delete the head branch after merging")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "List the pull requests that would be merged without merging them")
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt")
+	flags.StringVarP(&opts.output, "output", "o", "table", "Format of the report: table, json, csv or markdown")
+
+	return cmd
+}
+
+func runMerge(opts *mergeOptions, branchOrRun string) error {
+	switch opts.strategy {
+	case "merge", "squash", "rebase":
+	default:
+		return fmt.Errorf("unknown merge strategy %q, expected merge, squash or rebase", opts.strategy)
+	}
+
+	format, err := report.ParseFormat(opts.output)
+	if err != nil {
+		return err
+	}
+
+	branch, owners, host := resolveCampaign(branchOrRun, opts.owners)
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	owners, err = resolveOwners(client, host, owners)
+	if err != nil {
+		return err
+	}
+
+	prs, err := campaign.FindPullRequests(client, ownerContext(owners), branch)
+	if err != nil {
+		return err
+	}
+
+	if len(prs) == 0 {
+		fmt.Printf("No pull requests found for branch %s\n", branch)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching status of %d pull requests...\n", len(prs))
+	results := []report.Result{}
+	ready := []campaign.PullRequest{}
+	for i := range prs {
+		pr := &prs[i]
+		err = campaign.LoadStatus(client, pr)
+		if err != nil {
+			return fmt.Errorf("fetching status of %s#%d: %w", pr.Repository, pr.Number, err)
+		}
+
+		ok, reason := pr.ReadyToMerge()
+		if !ok {
+			results = append(results, report.Result{
+				Repository: pr.Repository,
+				Status:     report.StatusSkipped,
+				Err:        errors.New(reason),
+				PRURL:      pr.URL,
+			})
+			continue
+		}
+
+		ready = append(ready, *pr)
+	}
+
+	if len(ready) > 0 && isInteractive() && !opts.yes && !opts.dryRun {
+		if !confirm("Merge pull requests", makeMergeDescription(opts, ready)) {
+			fmt.Println("Aborting...")
+			return nil
+		}
+	}
+
+	for _, pr := range ready {
+		results = append(results, mergePullRequest(pr, opts))
+	}
+
+	return printReport(format, results)
+}
+
+// mergePullRequest merges pr, or only reports it as ready for a dry run.
+func mergePullRequest(pr campaign.PullRequest, opts *mergeOptions) report.Result {
+	result := report.Result{Repository: pr.Repository, PRURL: pr.URL}
+	if opts.dryRun {
+		result.Status = report.StatusReady
+		return result
+	}
+
+	r := pr.Repo()
+	start := time.Now()
+
+	r.Logf("Merging pull request %s", pr.URL)
+	err := r.MergePR(pr.URL, opts.strategy, opts.deleteBranch)
+	result.Duration = time.Since(start)
+	if err != nil {
+		r.Logf("Error merging PR: %s", err)
+		result.Fail(report.StepMerge, err)
+		return result
+	}
+
+	result.Status = report.StatusMerged
+	return result
+}
+
+func makeMergeDescription(opts *mergeOptions, prs []campaign.PullRequest) string {
+	var description strings.Builder
+
+	fmt.Fprintf(&description, "%-20s %s\n%-20s %t\n\n", "strategy:", opts.strategy, "delete branch:", opts.deleteBranch)
+	description.WriteString("Pull requests:\n")
+	for _, pr := range prs {
+		fmt.Fprintf(&description, "  %s\n", pr.URL)
+	}
+
+	return description.String()
+}
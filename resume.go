@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/jepomeroy/gh-bulk/internal/config"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+	"github.com/spf13/cobra"
+)
+
+func newResumeCmd() *cobra.Command {
+	var concurrency int
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "resume [<run-id>]",
+		Short: "Resume an interrupted run",
+		Long: `Resume a run from its journal, processing only the repositories that have not
+yet opened a pull request or been found to have no changes.
+
+Without a run id the recorded runs are listed.`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return runListJournals()
+			}
+
+			return runResume(args[0], concurrency, output)
+		},
+	}
+
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 1, "Number of repositories to process at the same time")
+	cmd.Flags().StringVarP(&output, "output", "o", "table", "Format of the end-of-run report: table, json, csv or markdown")
+
+	return cmd
+}
+
+func runListJournals() error {
+	ids, err := config.ListJournals()
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		fmt.Println("No runs recorded")
+		return nil
+	}
+
+	for _, id := range ids {
+		fmt.Println(id)
+	}
+
+	return nil
+}
+
+func runResume(id string, concurrency int, output string) error {
+	if concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", concurrency)
+	}
+
+	format, err := report.ParseFormat(output)
+	if err != nil {
+		return err
+	}
+
+	journal, err := config.LoadJournal(id)
+	if err != nil {
+		return err
+	}
+
+	pending := journal.Pending()
+	if len(pending) == 0 {
+		fmt.Printf("Run %s has no unfinished repositories\n", id)
+		return nil
+	}
+
+	host := resolveHost(journal.Plan.Host)
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	repos, err := repo.GetRepositories(client, ownerContext(journal.Plan.AllOwners()), pending)
+	if err != nil {
+		return err
+	}
+
+	user, c, err := loadLogin(client, host)
+	if err != nil {
+		return err
+	}
+
+	entry, _ := c.GetEntry(user.Login)
+
+	identity, err := resolveIdentity(entry, user, host)
+	if err != nil {
+		return err
+	}
+
+	transport, err := resolveTransport(entry, host)
+	if err != nil {
+		return err
+	}
+
+	// Unfinished repositories may already have pushed the branch or opened the pull request.
+	process := newProcessOptions(journal.Plan)
+	process.update = true
+	process.identity = identity
+	process.clone.Transport = transport
+	process.concurrency = concurrency
+	process.journal = journal
+	fmt.Fprintln(os.Stderr, makeDescription(process.command, process.commit, repos))
+
+	results := processRepos(repos, process)
+
+	return printReport(format, results)
+}
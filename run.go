@@ -0,0 +1,867 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cli/go-gh/v2/pkg/api"
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/jepomeroy/gh-bulk/internal/commit"
+	"github.com/jepomeroy/gh-bulk/internal/config"
+	"github.com/jepomeroy/gh-bulk/internal/execute"
+	"github.com/jepomeroy/gh-bulk/internal/plan"
+	"github.com/jepomeroy/gh-bulk/internal/repo"
+	"github.com/jepomeroy/gh-bulk/internal/report"
+	"github.com/spf13/cobra"
+)
+
+// runOptions holds the flags of the run command. Any value left empty is taken
+// from the plan file, or prompted for when running interactively.
+type runOptions struct {
+	planFile      string
+	owners        []string
+	filter        repo.Filter
+	repos         []string
+	reposFile     string
+	set           string
+	saveSet       string
+	branch        string
+	title         string
+	message       string
+	bodyFile      string
+	reviewers     []string
+	assignees     []string
+	labels        []string
+	milestone     string
+	draft         bool
+	codeOwners    bool
+	reviewersFile string
+	command       string
+	base          string
+	clone         string
+	sparsePaths   []string
+	yes           bool
+	dryRun        bool
+	concurrency   int
+	output        string
+	update        bool
+	forcePush     bool
+}
+
+// defaultRunOptions returns the runOptions of a run without flags, as gh bulk runs it.
+func defaultRunOptions() *runOptions {
+	return &runOptions{concurrency: 1, output: string(report.FormatTable)}
+}
+
+func newRunCmd() *cobra.Command {
+	opts := defaultRunOptions()
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run a command on each repository and open pull requests",
+		Long: `Run a command on each selected repository, commit the changes, and open a pull request.
+
+Values can come from a plan file, from flags, or from prompts. Flags override the
+plan file, and a prompt is only shown for a missing value when stdin is a terminal.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runRun(opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVarP(&opts.planFile, "file", "f", "", "Read the run from a YAML plan file")
+	addOwnerFlag(cmd, &opts.owners)
+	addFilterFlags(cmd, &opts.filter)
+	flags.StringSliceVar(&opts.repos, "repo", nil, "Repository to process as name or owner/name (repeatable)")
+	flags.StringVar(&opts.reposFile, "repos-file", "", "Read the repositories to choose from a file with one owner/name per line")
+	flags.StringVar(&opts.set, "set", "", "Process the repositories of a saved set")
+	flags.StringVar(&opts.saveSet, "save-set", "", "Save the selected repositories as a named set")
+	flags.StringVar(&opts.branch, "branch", "", "Name of the branch to create")
+	flags.StringVar(&opts.title, "title", "", "Pull request title")
+	flags.StringVar(&opts.message, "message", "", "Commit message, also used as the pull request body unless --body-file is given")
+	flags.StringVar(&opts.bodyFile, "body-file", "", "Render each pull request body from a Go template file")
+	flags.StringSliceVar(&opts.reviewers, "reviewer", nil, "Request a review from this user or org/team (repeatable)")
+	flags.StringVar(&opts.reviewersFile, "reviewers-file", "", "Read the reviewers of each repository from a YAML file, replacing --reviewer for those listed")
+	flags.BoolVar(&opts.codeOwners, "codeowners", false, "Also request a review from the CODEOWNERS of the changed paths")
+	flags.StringSliceVar(&opts.assignees, "assignee", nil, "Assign pull requests to this user, @me for yourself (repeatable)")
+	flags.StringSliceVar(&opts.labels, "label", nil, "Add this label to pull requests (repeatable)")
+	flags.StringVar(&opts.milestone, "milestone", "", "Add pull requests to the milestone with this name")
+	flags.BoolVar(&opts.draft, "draft", false, "Open pull requests as drafts")
+	flags.StringVar(&opts.command, "command", "", "Shell command to run in each repository")
+	flags.StringVar(&opts.base, "base", "", "Branch to start from and open pull requests against (default: each repository's default branch)")
+	flags.StringVar(&opts.clone, "clone", "", "Clone strategy: full, shallow, blobless or mirror (default full)")
+	flags.StringSliceVar(&opts.sparsePaths, "sparse", nil, "Check out only this directory of each repository (repeatable)")
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "Skip the confirmation prompt")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "Run the command and show the diff without committing, pushing, or opening pull requests")
+	flags.IntVarP(&opts.concurrency, "concurrency", "c", opts.concurrency, "Number of repositories to process at the same time")
+	flags.StringVarP(&opts.output, "output", "o", opts.output, "Format of the end-of-run report: table, json, csv or markdown")
+	flags.BoolVar(&opts.update, "update", false, "Reuse the branch and pull request of an earlier run instead of creating new ones")
+	flags.BoolVar(&opts.forcePush, "force-push", false, "With --update, recreate the branch from the base branch and force-push it")
+	cmd.MarkFlagsMutuallyExclusive("query", "repo", "repos-file", "set")
+
+	return cmd
+}
+
+// loadRepos sets the repositories of p from the saved set or repository file named by the flags,
+// if any. Saved sets are those of the host of p.
+func (opts *runOptions) loadRepos(p *plan.Plan) error {
+	var names []string
+
+	switch {
+	case opts.set != "":
+		sets, err := config.LoadRepoSets(p.Host)
+		if err != nil {
+			return err
+		}
+
+		names, err = sets.Get(opts.set)
+		if err != nil {
+			return err
+		}
+	case opts.reposFile != "":
+		var err error
+		names, err = repo.ReadList(opts.reposFile)
+		if err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if len(names) == 0 {
+		return errors.New("No repositories found")
+	}
+
+	p.Repos = names
+	p.Filter = repo.Filter{}
+	return nil
+}
+
+// apply overrides the values in p with the flags that were set.
+func (opts *runOptions) apply(p *plan.Plan) {
+	if len(opts.owners) > 0 {
+		p.Owner = ""
+		p.Owners = opts.owners
+	}
+
+	if !opts.filter.IsZero() {
+		p.Filter = p.Filter.With(opts.filter)
+		p.Repos = nil
+	}
+
+	if len(opts.repos) > 0 {
+		p.Repos = opts.repos
+		p.Filter = repo.Filter{}
+	}
+
+	if opts.branch != "" {
+		p.Branch = opts.branch
+	}
+
+	if opts.title != "" {
+		p.Title = opts.title
+	}
+
+	if opts.message != "" {
+		p.Message = opts.message
+	}
+
+	if opts.bodyFile != "" {
+		p.Body = ""
+		p.BodyFile = opts.bodyFile
+	}
+
+	if len(opts.reviewers) > 0 {
+		p.Reviewers = opts.reviewers
+	}
+
+	if opts.reviewersFile != "" {
+		p.RepoReviewers = nil
+		p.ReviewersFile = opts.reviewersFile
+	}
+
+	if opts.codeOwners {
+		p.CodeOwners = true
+	}
+
+	if len(opts.assignees) > 0 {
+		p.Assignees = opts.assignees
+	}
+
+	if len(opts.labels) > 0 {
+		p.Labels = opts.labels
+	}
+
+	if opts.milestone != "" {
+		p.Milestone = opts.milestone
+	}
+
+	if opts.draft {
+		p.Draft = true
+	}
+
+	if opts.command != "" {
+		p.Command = opts.command
+	}
+
+	if opts.base != "" {
+		p.Base = opts.base
+	}
+
+	if opts.clone != "" {
+		p.Clone = opts.clone
+	}
+
+	if len(opts.sparsePaths) > 0 {
+		p.SparsePaths = opts.sparsePaths
+	}
+
+	if opts.update {
+		p.Update = true
+	}
+
+	if opts.forcePush {
+		p.ForcePush = true
+	}
+}
+
+func runRun(opts *runOptions) error {
+	var p plan.Plan
+	var err error
+
+	if opts.concurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1, got %d", opts.concurrency)
+	}
+
+	format, err := report.ParseFormat(opts.output)
+	if err != nil {
+		return err
+	}
+
+	if opts.planFile != "" {
+		p, err = plan.Read(opts.planFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	opts.apply(&p)
+	interactive := isInteractive()
+
+	// The host is recorded with the run so that resume, status, merge and abort use it too.
+	p.Host = resolveHost(p.Host)
+
+	err = opts.loadRepos(&p)
+	if err != nil {
+		return err
+	}
+
+	err = p.LoadBody()
+	if err != nil {
+		return err
+	}
+
+	err = p.LoadReviewers()
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(p.Host)
+	if err != nil {
+		return err
+	}
+
+	user, c, err := loadLogin(client, p.Host)
+	if err != nil {
+		return err
+	}
+
+	owners := p.AllOwners()
+	if len(owners) == 0 {
+		owners, err = configuredOwners(c, user)
+		if err != nil {
+			return err
+		}
+	}
+
+	entry, _ := c.GetEntry(user.Login)
+
+	identity, err := resolveIdentity(entry, user, p.Host)
+	if err != nil {
+		return err
+	}
+
+	transport, err := resolveTransport(entry, p.Host)
+	if err != nil {
+		return err
+	}
+
+	ctx := ownerContext(owners)
+
+	// Repositories read from a file are offered for selection rather than all processed.
+	pick := interactive && opts.reposFile != ""
+
+	repos, err := resolveRepositories(client, ctx, &p, interactive, pick)
+	if err != nil {
+		return err
+	}
+
+	if opts.saveSet != "" {
+		sets, err := config.LoadRepoSets(p.Host)
+		if err != nil {
+			return err
+		}
+
+		err = sets.Save(opts.saveSet, repoNames(repos))
+		if err != nil {
+			return fmt.Errorf("saving repository set %s: %w", opts.saveSet, err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Saved %d repositories as set %s\n", len(repos), opts.saveSet)
+	}
+
+	if interactive {
+		c, err := commit.NewCommit(p.Commit())
+		if err != nil {
+			return err
+		}
+
+		p.Branch = c.BranchName
+		p.Title = c.PullRequestTitle
+		p.Message = c.CommitMessage
+
+		if p.Command == "" {
+			command, err := execute.GetCommand()
+			if err != nil {
+				return err
+			}
+
+			p.Command = command.CommandValue
+		}
+	}
+
+	err = p.Validate()
+	if err != nil {
+		return err
+	}
+
+	command := p.ExecCommand()
+	commit := p.Commit()
+
+	if interactive && !opts.yes && !opts.dryRun {
+		if !validate(command, commit, repos) {
+			fmt.Println("Aborting...")
+			return nil
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, makeDescription(command, commit, repos))
+	}
+
+	var journal *config.Journal
+	if !opts.dryRun {
+		journal, err = config.NewJournal(p, repoNames(repos))
+		if err != nil {
+			return fmt.Errorf("creating run journal: %w", err)
+		}
+
+		fmt.Fprintf(os.Stderr, "Run %s, resume with: gh bulk resume %s\n", journal.ID, journal.ID)
+	}
+
+	process := newProcessOptions(p)
+	process.identity = identity
+	process.clone.Transport = transport
+	process.dryRun = opts.dryRun
+	process.concurrency = opts.concurrency
+	process.journal = journal
+
+	results := processRepos(repos, process)
+
+	return printReport(format, results)
+}
+
+// resolveRepositories returns the repositories selected by p, prompting for a
+// search and selection when p selects none. When pick is set, the repositories listed
+// in p are offered for selection, all selected to start with. Interactive selections
+// are recorded in p.
+func resolveRepositories(client *api.RESTClient, ctx context.Context, p *plan.Plan, interactive bool, pick bool) ([]repo.Repository, error) {
+	var repos []repo.Repository
+	var err error
+
+	switch {
+	case len(p.Repos) > 0 && pick:
+		repos, err = repo.GetRepositories(client, ctx, p.Repos)
+		if err != nil {
+			return nil, err
+		}
+
+		return pickRepositories(repos, p, true)
+	case len(p.Repos) > 0:
+		repos, err = repo.GetRepositories(client, ctx, p.Repos)
+	case !p.Filter.IsZero():
+		repos, err = repo.FindRepositories(client, ctx, p.Filter)
+	case !interactive:
+		return nil, errors.New("no repositories given: pass --query, filters, --repo or a plan file")
+	default:
+		return selectRepositories(client, ctx, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repos) == 0 {
+		return nil, errors.New("No repositories found")
+	}
+
+	return repos, nil
+}
+
+func selectRepositories(client *api.RESTClient, ctx context.Context, p *plan.Plan) ([]repo.Repository, error) {
+	repoList, err := repo.FilterReposOptions(client, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return pickRepositories(repoList, p, false)
+}
+
+// pickRepositories prompts for a selection from repoList and records it in p.
+func pickRepositories(repoList []repo.Repository, p *plan.Plan, selectAll bool) ([]repo.Repository, error) {
+	if len(repoList) == 0 {
+		return nil, errors.New("No repositories found")
+	}
+
+	repos, err := repo.SelectRepositories(repoList, selectAll)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(repos) == 0 {
+		return nil, errors.New("No repositories selected")
+	}
+
+	p.Repos = repoNames(repos)
+
+	return repos, nil
+}
+
+// processOptions controls how processRepos handles each repository.
+type processOptions struct {
+	command execute.Command
+	commit  commit.Commit
+	clone   repo.CloneOptions
+	// identity authors, commits and signs the changes.
+	identity repo.Identity
+	// body renders the body of each pull request, unless it is nil.
+	body *commit.BodyTemplate
+	// repoReviewers replaces the reviewers of commit for the repositories it lists.
+	repoReviewers plan.RepoReviewers
+	// update reuses an existing branch and pull request, and forcePush recreates the branch
+	// from the base branch instead of adding a commit to it.
+	update      bool
+	forcePush   bool
+	dryRun      bool
+	concurrency int
+	// journal records each result as soon as it is known, unless it is nil.
+	journal *config.Journal
+}
+
+// newProcessOptions returns the processOptions that carry out p.
+func newProcessOptions(p plan.Plan) processOptions {
+	return processOptions{
+		command:       p.ExecCommand(),
+		commit:        p.Commit(),
+		clone:         p.CloneOptions(),
+		body:          p.BodyTemplate(),
+		repoReviewers: p.RepoReviewers,
+		update:        p.Update,
+		forcePush:     p.ForcePush,
+		concurrency:   1,
+	}
+}
+
+// processRepos processes repos with at most opts.concurrency of them in flight at once and
+// returns a result for each, in the order of repos.
+func processRepos(repos []repo.Repository, opts processOptions) []report.Result {
+	results := make([]report.Result, len(repos))
+	sem := make(chan struct{}, opts.concurrency)
+	var wg sync.WaitGroup
+
+	for i, r := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = processRepo(r, opts)
+			if opts.journal == nil {
+				return
+			}
+
+			err := opts.journal.Record(results[i])
+			if err != nil {
+				r.Logf("Error recording result in run journal: %s", err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return results
+}
+
+func processRepo(r repo.Repository, opts processOptions) (result report.Result) {
+	result = report.Result{Repository: r.FullName}
+	start := time.Now()
+
+	defer func() { clean(r) }()
+	defer func() { result.Duration = time.Since(start) }()
+
+	tempDir, err := os.MkdirTemp("", "gh-bulk-"+r.Owner+"-"+r.Name+"-")
+	if err != nil {
+		r.Logf("Error creating temporary directory: %s", err)
+		result.Fail(report.StepSetup, err)
+		return result
+	}
+
+	err = r.Clone(tempDir, opts.clone)
+	if err != nil {
+		r.Logf("Error cloning repository: %s", err)
+		result.Fail(report.StepClone, err)
+		return result
+	}
+
+	result.Status = report.StatusCloned
+
+	existing := false
+	if opts.update && !opts.forcePush {
+		existing, err = r.CheckoutBranch(opts.commit)
+	} else {
+		err = r.CreateBranch(opts.commit)
+	}
+	if err != nil {
+		r.Logf("Error creating branch: %s", err)
+		result.Fail(report.StepBranch, err)
+		return result
+	}
+
+	err = opts.command.Execute(r.Dir())
+	if err != nil {
+		r.Logf("Error executing command: %s", err)
+		result.Fail(report.StepCommand, err)
+		return result
+	}
+
+	changed, err := r.HasChanges()
+	if err != nil {
+		r.Logf("Error checking worktree status: %s", err)
+		result.Fail(report.StepStatus, err)
+		return result
+	}
+
+	if !changed && (!existing || opts.dryRun) {
+		r.Logf("No changes, skipping commit and pull request")
+		result.Status = report.StatusUnchanged
+		return result
+	}
+
+	if opts.dryRun {
+		err = previewChanges(r)
+		if err == nil {
+			err = previewPR(r, opts)
+		}
+		if err != nil {
+			result.Fail(report.StepDiff, err)
+			return result
+		}
+
+		result.Status = report.StatusChanged
+		return result
+	}
+
+	if changed {
+		err = r.CommitAndPush(opts.commit, opts.identity, opts.forcePush)
+		if err != nil {
+			r.Logf("Error committing and pushing: %s", err)
+			result.Fail(report.StepPush, err)
+			return result
+		}
+	} else {
+		// The existing branch already carries the changes, but its pull request may be
+		// missing or need the new title and message.
+		r.Logf("No new changes on existing branch %s", opts.commit.BranchName)
+	}
+
+	opts.commit.PullRequestBody, err = renderBody(r, opts)
+	if err != nil {
+		r.Logf("Error rendering PR body: %s", err)
+		result.Fail(report.StepPR, err)
+		return result
+	}
+
+	opts.commit.Reviewers = resolveReviewers(r, opts)
+
+	if opts.update && updatePR(r, opts, &result) {
+		return result
+	}
+
+	url, err := r.CreatePR(opts.commit)
+	if err != nil {
+		r.Logf("Error creating PR: %s", err)
+		result.Fail(report.StepPR, err)
+		return result
+	}
+
+	r.Logf("Opened pull request %s", url)
+	result.Status = report.StatusPROpened
+	result.PRURL = url
+	return result
+}
+
+// updatePR edits the open pull request from opts.commit.BranchName, if there is one, to
+// use the commit's title and message. It reports whether result is final, which is the
+// case unless no pull request was found.
+func updatePR(r repo.Repository, opts processOptions, result *report.Result) bool {
+	url, err := r.FindPR(opts.commit)
+	if err != nil {
+		r.Logf("Error finding existing PR: %s", err)
+		result.Fail(report.StepPR, err)
+		return true
+	}
+
+	if url == "" {
+		return false
+	}
+
+	err = r.EditPR(url, opts.commit)
+	if err != nil {
+		r.Logf("Error updating PR: %s", err)
+		result.Fail(report.StepPR, err)
+		return true
+	}
+
+	r.Logf("Updated pull request %s", url)
+	result.Status = report.StatusPRUpdated
+	result.PRURL = url
+	return true
+}
+
+// previewChanges prints the diff the command left in r's worktree.
+func previewChanges(r repo.Repository) error {
+	diff, err := r.Diff()
+	if err != nil {
+		r.Logf("Error diffing worktree: %s", err)
+		return err
+	}
+
+	r.Logf("Changes:\n%s", diff)
+	return nil
+}
+
+// renderBody returns the body of the pull request for r from opts.body, or an empty string
+// to use the commit message when there is no body template.
+func renderBody(r repo.Repository, opts processOptions) (string, error) {
+	if opts.body == nil {
+		return "", nil
+	}
+
+	diffStat, err := r.DiffStat(opts.commit)
+	if err != nil {
+		return "", err
+	}
+
+	data := commit.BodyData{
+		Repository:    r.FullName,
+		Owner:         r.Owner,
+		Name:          r.Name,
+		DefaultBranch: r.DefaultBranch,
+		BaseBranch:    r.Base(opts.commit),
+		Branch:        opts.commit.BranchName,
+		Title:         opts.commit.PullRequestTitle,
+		Message:       opts.commit.CommitMessage,
+		Command:       opts.command.CommandValue,
+		DiffStat:      diffStat,
+	}
+	if opts.journal != nil {
+		data.CampaignID = opts.journal.ID
+	}
+
+	return opts.body.Render(data)
+}
+
+// resolveReviewers returns who is asked to review the pull request for r: the reviewers listed
+// for r in opts.repoReviewers or else those of the run, and the code owners of the changed
+// paths when asked for. The gh user is left out, as GitHub refuses review requests from the
+// author of a pull request.
+func resolveReviewers(r repo.Repository, opts processOptions) []string {
+	candidates := opts.commit.Reviewers
+	if listed, ok := opts.repoReviewers.For(r.Owner, r.Name); ok {
+		candidates = listed
+	}
+
+	if opts.commit.CodeOwners {
+		owners, err := r.CodeOwners(opts.commit)
+		if err != nil {
+			r.Logf("Error reading CODEOWNERS, not requesting reviews from code owners: %s", err)
+		}
+		candidates = append(slices.Clone(candidates), owners...)
+	}
+
+	reviewers := []string{}
+	seen := map[string]bool{strings.ToLower(UserAuth.Login): true}
+	for _, reviewer := range candidates {
+		if !seen[strings.ToLower(reviewer)] {
+			seen[strings.ToLower(reviewer)] = true
+			reviewers = append(reviewers, reviewer)
+		}
+	}
+
+	return reviewers
+}
+
+// previewPR prints the body rendered for r, when there is a body template, and the reviewers
+// its pull request would ask for.
+func previewPR(r repo.Repository, opts processOptions) error {
+	if opts.body != nil {
+		body, err := renderBody(r, opts)
+		if err != nil {
+			r.Logf("Error rendering PR body: %s", err)
+			return err
+		}
+
+		r.Logf("Pull request body:\n%s", body)
+	}
+
+	reviewers := resolveReviewers(r, opts)
+	if len(reviewers) > 0 {
+		r.Logf("Reviewers: %s", strings.Join(reviewers, ", "))
+	}
+
+	return nil
+}
+
+// printReport writes results to stdout in format, followed by a count of each status
+// on stderr when the report is a table.
+func printReport(format report.Format, results []report.Result) error {
+	if format != report.FormatTable {
+		return report.Write(os.Stdout, format, results)
+	}
+
+	t := term.FromEnv()
+	width, _, _ := t.Size()
+	fmt.Println()
+	err := report.WriteTable(tableprinter.New(os.Stdout, t.IsTerminalOutput(), width), results)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(os.Stderr, makeSummary(results))
+	return nil
+}
+
+// makeSummary returns a one line count of results by status.
+func makeSummary(results []report.Result) string {
+	counts := report.Counts(results)
+	parts := []string{}
+
+	for _, status := range []report.Status{
+		report.StatusPROpened,
+		report.StatusPRUpdated,
+		report.StatusChanged,
+		report.StatusReady,
+		report.StatusMerged,
+		report.StatusClosed,
+		report.StatusBranchDeleted,
+		report.StatusUnchanged,
+		report.StatusSkipped,
+		report.StatusFailed,
+	} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+
+	return fmt.Sprintf("\n%d repositories: %s", len(results), strings.Join(parts, ", "))
+}
+
+// repoNames returns the owner/name of each of repos.
+func repoNames(repos []repo.Repository) []string {
+	names := []string{}
+	for _, r := range repos {
+		names = append(names, r.FullName)
+	}
+
+	return names
+}
+
+func clean(r repo.Repository) {
+	err := r.Clean()
+	if err != nil {
+		r.Logf("Error cleaning %s, %s", r.Name, err)
+	}
+}
+
+func makeDescription(command execute.Command, commit commit.Commit, selectedRepos []repo.Repository) string {
+	var description strings.Builder
+
+	fmt.Fprintf(&description, "%-20s %s\n%-20s %s\n%-20s %s\n%-20s %s\n\n",
+		"command:",
+		command.CommandValue,
+		"branch name:",
+		commit.BranchName,
+		"pull request title:",
+		commit.PullRequestTitle,
+		"commit message:",
+		commit.CommitMessage,
+	)
+
+	if commit.BaseBranch != "" {
+		fmt.Fprintf(&description, "%-20s %s\n\n", "base branch:", commit.BaseBranch)
+	}
+
+	options := []string{}
+	for _, option := range []struct {
+		name   string
+		values []string
+	}{
+		{"reviewers:", commit.Reviewers},
+		{"assignees:", commit.Assignees},
+		{"labels:", commit.Labels},
+	} {
+		if len(option.values) > 0 {
+			options = append(options, fmt.Sprintf("%-20s %s\n", option.name, strings.Join(option.values, ", ")))
+		}
+	}
+	if commit.CodeOwners {
+		options = append(options, fmt.Sprintf("%-20s %s\n", "code owners:", "requested"))
+	}
+	if commit.Milestone != "" {
+		options = append(options, fmt.Sprintf("%-20s %s\n", "milestone:", commit.Milestone))
+	}
+	if commit.Draft {
+		options = append(options, fmt.Sprintf("%-20s %s\n", "draft:", "yes"))
+	}
+	if len(options) > 0 {
+		description.WriteString(strings.Join(options, "") + "\n")
+	}
+
+	description.WriteString("Repositories:\n")
+	for _, r := range selectedRepos {
+		fmt.Fprintf(&description, "  %s\n", r.FullName)
+	}
+
+	return description.String()
+}
+
+func validate(command execute.Command, commit commit.Commit, selectedRepos []repo.Repository) bool {
+	return confirm("Verify Info", makeDescription(command, commit, selectedRepos))
+}
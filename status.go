@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/cli/go-gh/v2/pkg/tableprinter"
+	"github.com/cli/go-gh/v2/pkg/term"
+	"github.com/jepomeroy/gh-bulk/internal/campaign"
+	"github.com/jepomeroy/gh-bulk/internal/config"
+	"github.com/spf13/cobra"
+)
+
+func newStatusCmd() *cobra.Command {
+	var owners []string
+
+	cmd := &cobra.Command{
+		Use:   "status <branch-or-run>",
+		Short: "Show the pull requests opened from a branch",
+		Long: `Show the state, review decision, checks, and mergeability of every pull request
+opened from a branch. The argument is either the branch name or the id of a recorded run.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runStatus(owners, args[0])
+		},
+	}
+
+	addOwnerFlag(cmd, &owners)
+
+	return cmd
+}
+
+// resolveCampaign returns the branch, owners and host of branchOrRun, which is either a branch
+// name or the id of a recorded run whose plan supplies them. Explicit owners and --hostname win.
+func resolveCampaign(branchOrRun string, owners []string) (string, []string, string) {
+	journal, err := config.LoadJournal(branchOrRun)
+	if err != nil {
+		return branchOrRun, owners, resolveHost("")
+	}
+
+	if len(owners) == 0 {
+		owners = journal.Plan.AllOwners()
+	}
+
+	return journal.Plan.Branch, owners, resolveHost(journal.Plan.Host)
+}
+
+func runStatus(owners []string, branchOrRun string) error {
+	branch, owners, host := resolveCampaign(branchOrRun, owners)
+
+	client, err := newClient(host)
+	if err != nil {
+		return err
+	}
+
+	owners, err = resolveOwners(client, host, owners)
+	if err != nil {
+		return err
+	}
+
+	prs, err := campaign.FindPullRequests(client, ownerContext(owners), branch)
+	if err != nil {
+		return err
+	}
+
+	if len(prs) == 0 {
+		fmt.Printf("No pull requests found for branch %s\n", branch)
+		return nil
+	}
+
+	fmt.Fprintf(os.Stderr, "Fetching status of %d pull requests...\n", len(prs))
+	for i := range prs {
+		err = campaign.LoadStatus(client, &prs[i])
+		if err != nil {
+			return fmt.Errorf("fetching status of %s#%d: %w", prs[i].Repository, prs[i].Number, err)
+		}
+	}
+
+	t := term.FromEnv()
+	width, _, _ := t.Size()
+	tp := tableprinter.New(os.Stdout, t.IsTerminalOutput(), width)
+	tp.AddHeader([]string{"REPOSITORY", "PR", "STATE", "REVIEW", "CHECKS", "MERGEABLE", "URL"})
+	for _, pr := range prs {
+		tp.AddField(pr.Repository)
+		tp.AddField("#" + strconv.Itoa(pr.Number))
+		tp.AddField(pr.State)
+		tp.AddField(pr.ReviewDecision)
+		tp.AddField(pr.Checks)
+		tp.AddField(pr.Mergeable)
+		tp.AddField(pr.URL)
+		tp.EndRow()
+	}
+
+	return tp.Render()
+}